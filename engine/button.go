@@ -1,12 +1,16 @@
 package engine
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// HOVER_BRIGHTEN is added to each channel of the background of a button
+// that brightens on hover, which stops at HOVER_MAX
+const (
+	HOVER_BRIGHTEN = 0x66
+	HOVER_MAX      = 0xCF
+)
+
 type Button struct {
 	Rect                sdl.Rect
 	BackgroundColor     sdl.Color
@@ -22,6 +26,9 @@ type Button struct {
 	OnMouseLeave func(e *Engine)
 	OnClick      func(e *Engine)
 
+	// Shortcuts are input actions that click the button when pressed
+	Shortcuts []byte
+
 	WidgetID string
 
 	isVisible bool
@@ -29,98 +36,119 @@ type Button struct {
 	isHovered bool
 }
 
-func (b *Button) useArgs(args []interface{}) error {
-	var ok bool
+// ButtonOptions configures a button. Fields left at their zero value take
+// the default noted beside them.
+type ButtonOptions struct {
+	Pos  sdl.Point
+	Size sdl.Point
+
+	// Color is the background, to which the button returns after hovering
+	// brightens it. The default is transparent.
+	Color sdl.Color
+
+	Text      string
+	TextColor sdl.Color // default DEFAULT_TEXT_COLOR
+
+	FontName string // default DEFAULT_FONT
+	FontSize int    // default DEFAULT_FONT_SIZE
+
+	// The handlers default to doing nothing
+	OnMouseEnter func(e *Engine)
+	OnMouseLeave func(e *Engine)
+	OnClick      func(e *Engine)
+
+	// Brighten lightens the background while the mouse is over the button,
+	// before running OnMouseEnter, and restores it before OnMouseLeave
+	Brighten bool
+
+	// Shortcuts are input actions that click the button when pressed
+	Shortcuts []byte
+}
+
+// useOptions fills in the defaults of the options and applies them, naming
+// the widget in any validation error
+func (b *Button) useOptions(widget string, opts ButtonOptions) error {
+	if opts.TextColor == (sdl.Color{}) {
+		opts.TextColor = DEFAULT_TEXT_COLOR
+	}
+
+	if opts.FontName == "" {
+		opts.FontName = DEFAULT_FONT
+	}
 
-	pos := sdl.Point{X: b.Rect.X, Y: b.Rect.Y}
-	size := sdl.Point{X: b.Rect.W, Y: b.Rect.H}
-	bg := b.BackgroundColor
-	txt := b.Text
-	txtColor := b.TextColor
-	fntName := b.FontName
-	fntSize := b.FontSize
-	ome := b.OnMouseEnter
-	oml := b.OnMouseLeave
-	oc := b.OnClick
+	if opts.FontSize == 0 {
+		opts.FontSize = DEFAULT_FONT_SIZE
+	}
 
-	for i := range len(args) {
-		if i > 9 {
-			break
+	for _, handler := range []*func(e *Engine){&opts.OnMouseEnter, &opts.OnMouseLeave, &opts.OnClick} {
+		if *handler == nil {
+			*handler = func(e *Engine) {}
 		}
+	}
 
-		arg := args[i]
-		var t string
-
-		switch i {
-		case 0:
-			pos, ok = arg.(sdl.Point)
-			t = "sdl.Point"
-		case 1:
-			size, ok = arg.(sdl.Point)
-			t = "sdl.Point"
-		case 2:
-			bg, ok = arg.(sdl.Color)
-			t = "sdl.Color"
-		case 3:
-			txt, ok = arg.(string)
-			t = "string"
-		case 4:
-			txtColor, ok = arg.(sdl.Color)
-			t = "sdl.Color"
-		case 5:
-			fntName, ok = arg.(string)
-			t = "string"
-		case 6:
-			fntSize, ok = arg.(int)
-			t = "int"
-		case 7:
-			ome, ok = arg.(func(*Engine))
-			t = "func(*Engine)"
-		case 8:
-			oml, ok = arg.(func(*Engine))
-			t = "func(*Engine)"
-		case 9:
-			oc, ok = arg.(func(*Engine))
-			t = "func(*Engine)"
+	if opts.Brighten {
+		enter, leave := opts.OnMouseEnter, opts.OnMouseLeave
+
+		opts.OnMouseEnter = func(e *Engine) {
+			b.brighten()
+			enter(e)
 		}
 
-		if !ok {
-			return fmt.Errorf("invalid argument at index %d: expected %s, got %T", i, t, arg)
+		opts.OnMouseLeave = func(e *Engine) {
+			b.BackgroundColor = b.InitBackgroundColor
+			leave(e)
 		}
 	}
 
-	b.Rect = sdl.Rect{X: pos.X, Y: pos.Y, W: size.X, H: size.Y}
-	b.BackgroundColor = bg
-	b.InitBackgroundColor = bg
-	b.Text = txt
-	b.TextColor = txtColor
-	b.FontName = fntName
-	b.FontSize = fntSize
-	b.OnMouseEnter = ome
-	b.OnMouseLeave = oml
-	b.OnClick = oc
+	err := checkText(widget, opts.Size, opts.FontName, opts.FontSize)
+
+	if err != nil {
+		return err
+	}
+
+	b.Rect = sdl.Rect{X: opts.Pos.X, Y: opts.Pos.Y, W: opts.Size.X, H: opts.Size.Y}
+	b.BackgroundColor = opts.Color
+	b.InitBackgroundColor = opts.Color
+	b.Text = opts.Text
+	b.TextColor = opts.TextColor
+	b.FontName = opts.FontName
+	b.FontSize = opts.FontSize
+	b.OnMouseEnter = opts.OnMouseEnter
+	b.OnMouseLeave = opts.OnMouseLeave
+	b.OnClick = opts.OnClick
+	b.Shortcuts = opts.Shortcuts
 
 	return nil
 }
 
-func (b *Button) Setup(s Scene, args []interface{}) error {
-	*b = Button{}
-
-	num_buttons := 0
+// brighten lightens each channel of the background by HOVER_BRIGHTEN
+func (b *Button) brighten() {
+	b.BackgroundColor = sdl.Color{
+		R: uint8(min(uint16(HOVER_MAX), uint16(b.BackgroundColor.R)+HOVER_BRIGHTEN)),
+		G: uint8(min(uint16(HOVER_MAX), uint16(b.BackgroundColor.G)+HOVER_BRIGHTEN)),
+		B: uint8(min(uint16(HOVER_MAX), uint16(b.BackgroundColor.B)+HOVER_BRIGHTEN)),
+		A: b.BackgroundColor.A,
+	}
+}
 
-	for _, key := range s.GetWidgetIDs() {
-		prefix := strings.Split(key, "_")[0]
+// Setup adds the button to a scene with the ButtonOptions in args
+func (b *Button) Setup(s Scene, args []interface{}) error {
+	opts, err := widgetOptions[ButtonOptions]("button", args)
 
-		if prefix == "Button" {
-			num_buttons++
-		}
+	if err != nil {
+		return err
 	}
 
-	id := "Button_" + fmt.Sprintf("%d", num_buttons)
+	return b.SetupWith(s, opts)
+}
+
+// SetupWith adds the button to a scene, configured by opts
+func (b *Button) SetupWith(s Scene, opts ButtonOptions) error {
+	*b = Button{}
 
-	b.WidgetID = id
+	b.WidgetID = nextWidgetID(s, "Button")
 
-	err := b.useArgs(args)
+	err := b.useOptions("button", opts)
 
 	if err != nil {
 		return err
@@ -152,6 +180,10 @@ func (b *Button) Resize(size sdl.Point) {
 }
 
 func (b *Button) Draw(e *Engine) error {
+	if !b.isVisible {
+		return nil
+	}
+
 	e.Renderer.SetDrawColor(b.BackgroundColor.R, b.BackgroundColor.G, b.BackgroundColor.B, b.BackgroundColor.A)
 	e.Renderer.FillRect(&b.Rect)
 	e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
@@ -196,3 +228,16 @@ func (b *Button) Click(e *Engine, pos sdl.Point) {
 		b.OnClick(e)
 	}
 }
+
+func (b *Button) Input(e *Engine, action byte, pressed byte) {
+	if !b.isVisible || !b.isActive || pressed != PRESSED {
+		return
+	}
+
+	for _, shortcut := range b.Shortcuts {
+		if shortcut == action {
+			b.OnClick(e)
+			return
+		}
+	}
+}
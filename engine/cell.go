@@ -0,0 +1,402 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	CAGE_INSET = int32(3)
+	CAGE_DASH  = int32(4)
+
+	// REGION_INSET is how far a region border reaches into the cell past
+	// the gap around it
+	REGION_INSET = int32(2)
+)
+
+// Cell is a button that shows either its text or a grid of smaller notes
+type Cell struct {
+	Button
+
+	Notes        []string
+	NoteColumns  int
+	NoteColor    sdl.Color
+	NoteFontSize int
+
+	// CageEdges marks the sides of the cell on the outline of its cage, in
+	// the order top, right, bottom, left. CageGaps holds the space to the
+	// neighboring cell on each side, which open sides reach across so the
+	// outline joins up with the neighbor's.
+	CageEdges [4]bool
+	CageGaps  [4]int32
+	CageColor sdl.Color
+
+	// Corner is small text drawn in the top left, such as a cage sum
+	Corner string
+
+	// RegionEdges marks the sides of the cell on the border of its jigsaw
+	// region, in the same order as CageEdges. RegionGap is the space
+	// between cells, half of which each side of a border fills.
+	RegionEdges [4]bool
+	RegionGap   int32
+	RegionColor sdl.Color
+
+	// Strokes are the pieces of constraint lines crossing the cell and
+	// Discs the circles on it, drawn over the background and under the digits
+	Strokes []Stroke
+	Discs   []Disc
+
+	// Marks are the markers on the sides of the cell. Each is handed to both
+	// cells it sits between and drawn by each clipped to its MarkArea, the
+	// cell and half the gap around it, so neither background hides it.
+	Marks    []Mark
+	MarkArea sdl.Rect
+}
+
+// Stroke is a straight piece of a constraint line
+type Stroke struct {
+	From  sdl.FPoint
+	To    sdl.FPoint
+	Width float32
+	Color sdl.Color
+}
+
+// Mark is a marker between two cells, drawn as its discs and strokes with
+// any text centered over them
+type Mark struct {
+	Discs   []Disc
+	Strokes []Stroke
+	Text    string
+	Center  sdl.FPoint
+	Color   sdl.Color
+}
+
+// Disc is a circle on a cell, filled when Width is 0 and otherwise an
+// outline that many pixels thick
+type Disc struct {
+	Center sdl.FPoint
+	Radius float32
+	Width  float32
+	Color  sdl.Color
+}
+
+// CellOptions configures a cell: the button it is drawn as, and the grid
+// its notes are laid out in. Fields left at their zero value take the
+// default noted beside them.
+type CellOptions struct {
+	ButtonOptions
+
+	NoteColumns  int       // default 3
+	NoteColor    sdl.Color // default the TextColor of the button
+	NoteFontSize int       // default 14
+}
+
+// Setup adds the cell to a scene with the CellOptions in args
+func (c *Cell) Setup(s Scene, args []interface{}) error {
+	opts, err := widgetOptions[CellOptions]("cell", args)
+
+	if err != nil {
+		return err
+	}
+
+	return c.SetupWith(s, opts)
+}
+
+// SetupWith adds the cell to a scene, configured by opts
+func (c *Cell) SetupWith(s Scene, opts CellOptions) error {
+	*c = Cell{}
+
+	c.WidgetID = nextWidgetID(s, "Cell")
+
+	err := c.useOptions("cell", opts.ButtonOptions)
+
+	if err != nil {
+		return err
+	}
+
+	if opts.NoteColumns == 0 {
+		opts.NoteColumns = 3
+	}
+
+	if opts.NoteColor == (sdl.Color{}) {
+		opts.NoteColor = c.TextColor
+	}
+
+	if opts.NoteFontSize == 0 {
+		opts.NoteFontSize = 14
+	}
+
+	if opts.NoteColumns < 0 {
+		return fmt.Errorf("invalid cell option NoteColumns: %d is negative", opts.NoteColumns)
+	}
+
+	if !slices.Contains(FONT_SIZES, opts.NoteFontSize) {
+		return fmt.Errorf("invalid cell option NoteFontSize: fonts are not loaded in size %d", opts.NoteFontSize)
+	}
+
+	c.NoteColumns = opts.NoteColumns
+	c.NoteColor = opts.NoteColor
+	c.NoteFontSize = opts.NoteFontSize
+
+	*c.Visible() = true
+	*c.Active() = true
+	c.isHovered = false
+
+	return s.InsertWidget(c)
+}
+
+func (c *Cell) Delete(s Scene) error {
+	return s.DeleteWidget(c.WidgetID)
+}
+
+func (c *Cell) Draw(e *Engine) error {
+	if !c.isVisible {
+		return nil
+	}
+
+	e.Renderer.SetDrawColor(c.BackgroundColor.R, c.BackgroundColor.G, c.BackgroundColor.B, c.BackgroundColor.A)
+	e.Renderer.FillRect(&c.Rect)
+	e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
+
+	err := c.drawOverlay(e)
+
+	if err != nil {
+		return err
+	}
+
+	if c.Text != "" || len(c.Notes) == 0 {
+		err = c.drawText(e)
+	} else {
+		err = c.drawNotes(e)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = c.drawCage(e)
+
+	if err != nil {
+		return err
+	}
+
+	c.drawRegion(e)
+
+	err = c.drawCorner(e)
+
+	if err != nil {
+		return err
+	}
+
+	return c.drawMarks(e)
+}
+
+func (c *Cell) drawOverlay(e *Engine) error {
+	return drawShapes(e, c.Strokes, c.Discs)
+}
+
+// drawMarks draws the markers on the sides of the cell over everything else
+// in it, leaving the part beyond its MarkArea to the neighbor
+func (c *Cell) drawMarks(e *Engine) error {
+	if len(c.Marks) == 0 {
+		return nil
+	}
+
+	e.Renderer.SetClipRect(&c.MarkArea)
+	defer e.Renderer.SetClipRect(nil)
+
+	for _, mark := range c.Marks {
+		err := drawShapes(e, mark.Strokes, mark.Discs)
+
+		if err != nil {
+			return err
+		}
+
+		if mark.Text == "" {
+			continue
+		}
+
+		rect := sdl.Rect{X: int32(mark.Center.X) - c.Rect.W/2, Y: int32(mark.Center.Y) - c.Rect.H/2, W: c.Rect.W, H: c.Rect.H}
+		pos, err := e.CenterTextInRect(c.FontName, c.NoteFontSize, []string{mark.Text}, rect)
+
+		if err != nil {
+			return err
+		}
+
+		err = e.DrawText(c.FontName, c.NoteFontSize, []string{mark.Text}, mark.Color, pos)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drawShapes draws strokes and then discs, filling discs of no width and
+// outlining the rest
+func drawShapes(e *Engine, strokes []Stroke, discs []Disc) error {
+	for _, stroke := range strokes {
+		err := e.DrawThickLine(stroke.From, stroke.To, stroke.Width, stroke.Color)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, disc := range discs {
+		var err error
+
+		if disc.Width == 0 {
+			err = e.DrawCircle(disc.Center, disc.Radius, disc.Color)
+		} else {
+			err = e.DrawRing(disc.Center, disc.Radius, disc.Width, disc.Color)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Cell) drawText(e *Engine) error {
+	centered_pos, err := e.CenterTextInRect(c.FontName, c.FontSize, []string{c.Text}, c.Rect)
+
+	if err != nil {
+		return err
+	}
+
+	return e.DrawText(c.FontName, c.FontSize, []string{c.Text}, c.TextColor, centered_pos)
+}
+
+func (c *Cell) drawNotes(e *Engine) error {
+	// Lay the notes out left to right, top to bottom
+	columns := int32(max(1, c.NoteColumns))
+	rows := (int32(len(c.Notes)) + columns - 1) / columns
+	note_width := c.Rect.W / columns
+	note_height := c.Rect.H / rows
+
+	for i, note := range c.Notes {
+		if note == "" {
+			continue
+		}
+
+		rect := sdl.Rect{
+			X: c.Rect.X + int32(i)%columns*note_width,
+			Y: c.Rect.Y + int32(i)/columns*note_height,
+			W: note_width,
+			H: note_height,
+		}
+
+		centered_pos, err := e.CenterTextInRect(c.FontName, c.NoteFontSize, []string{note}, rect)
+
+		if err != nil {
+			return err
+		}
+
+		err = e.DrawText(c.FontName, c.NoteFontSize, []string{note}, c.NoteColor, centered_pos)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drawCage outlines the cage sides of the cell just inside its edges,
+// running each line on past the cell where the outline carries on
+func (c *Cell) drawCage(e *Engine) error {
+	left := c.Rect.X + CAGE_INSET
+	top := c.Rect.Y + CAGE_INSET
+	right := c.Rect.X + c.Rect.W - 1 - CAGE_INSET
+	bottom := c.Rect.Y + c.Rect.H - 1 - CAGE_INSET
+
+	// Where a side is open the line reaches the neighbor's inset outline
+	reach := [4]int32{}
+
+	for side, edge := range c.CageEdges {
+		if !edge {
+			reach[side] = c.CageGaps[side] + 2*CAGE_INSET + 1
+		}
+	}
+
+	lines := [4][2]sdl.Point{
+		{{X: left - reach[3], Y: top}, {X: right + reach[1], Y: top}},
+		{{X: right, Y: top - reach[0]}, {X: right, Y: bottom + reach[2]}},
+		{{X: left - reach[3], Y: bottom}, {X: right + reach[1], Y: bottom}},
+		{{X: left, Y: top - reach[0]}, {X: left, Y: bottom + reach[2]}},
+	}
+
+	for side, edge := range c.CageEdges {
+		if !edge {
+			continue
+		}
+
+		err := e.DrawDashedLine(lines[side][0], lines[side][1], CAGE_DASH, c.CageColor)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drawRegion fills the gap along the sides of the cell on a region border
+// out to halfway to the neighbor, which fills the other half. Each band runs
+// on into the gaps at its ends so borders meet at the corners.
+func (c *Cell) drawRegion(e *Engine) {
+	half := (c.RegionGap + 1) / 2
+	width := half + REGION_INSET
+	r := c.Rect
+
+	bands := [4]sdl.Rect{
+		{X: r.X - half, Y: r.Y - half, W: r.W + 2*half, H: width},
+		{X: r.X + r.W - REGION_INSET, Y: r.Y - half, W: width, H: r.H + 2*half},
+		{X: r.X - half, Y: r.Y + r.H - REGION_INSET, W: r.W + 2*half, H: width},
+		{X: r.X - half, Y: r.Y - half, W: width, H: r.H + 2*half},
+	}
+
+	e.Renderer.SetDrawColor(c.RegionColor.R, c.RegionColor.G, c.RegionColor.B, c.RegionColor.A)
+
+	for side, edge := range c.RegionEdges {
+		if edge {
+			e.Renderer.FillRect(&bands[side])
+		}
+	}
+
+	e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
+}
+
+// drawCorner writes the corner text over a patch of background so the cage
+// outline does not run through it
+func (c *Cell) drawCorner(e *Engine) error {
+	if c.Corner == "" {
+		return nil
+	}
+
+	font, err := e.font(c.FontName, c.NoteFontSize)
+
+	if err != nil {
+		return err
+	}
+
+	width, height, err := e.GetTextSize(font, c.Corner)
+
+	if err != nil {
+		return err
+	}
+
+	pos := sdl.Point{X: c.Rect.X + 1, Y: c.Rect.Y}
+
+	e.Renderer.SetDrawColor(c.BackgroundColor.R, c.BackgroundColor.G, c.BackgroundColor.B, c.BackgroundColor.A)
+	e.Renderer.FillRect(&sdl.Rect{X: pos.X, Y: pos.Y, W: int32(width) + 2, H: int32(height)})
+	e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
+
+	return e.DrawText(c.FontName, c.NoteFontSize, []string{c.Corner}, c.CageColor, sdl.Point{X: pos.X + 1, Y: pos.Y})
+}
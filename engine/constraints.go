@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"math"
+	"slices"
+
+	"main/sudoku"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	SHADED_CELL_COLOR     = sdl.Color{R: 0xC8, G: 0xBE, B: 0xA0, A: 0xFF}
+	CONSTRAINT_LINE_COLOR = sdl.Color{R: 0x94, G: 0x9C, B: 0xB4, A: 0xFF}
+	THERMO_COLOR          = sdl.Color{R: 0xB4, G: 0xB4, B: 0xB4, A: 0xFF}
+	ARROW_COLOR           = sdl.Color{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}
+	PALINDROME_COLOR      = sdl.Color{R: 0x9C, G: 0xA8, B: 0x9C, A: 0xFF}
+	RENBAN_COLOR          = sdl.Color{R: 0xD2, G: 0xAA, B: 0xE6, A: 0xFF}
+	KROPKI_WHITE_COLOR    = sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	KROPKI_BLACK_COLOR    = sdl.Color{R: 0x20, G: 0x20, B: 0x20, A: 0xFF}
+	XV_DISC_COLOR         = sdl.Color{R: 0xF4, G: 0xF0, B: 0xE6, A: 0xFF}
+	XV_TEXT_COLOR         = sdl.Color{R: 0x20, G: 0x20, B: 0x20, A: 0xFF}
+	GREATER_COLOR         = sdl.Color{R: 0x30, G: 0x30, B: 0x40, A: 0xFF}
+)
+
+const (
+	// The thin lines are sized as a share of the cell, so they grow with
+	// the grid and the scale of the window, but never below a pixel
+	CONSTRAINT_LINE_WIDTH = float32(0.05)
+	ARROW_LINE_WIDTH      = float32(0.035)
+	MARKER_LINE_WIDTH     = float32(0.035)
+
+	// The thick lines, bulbs, circles and arrowheads are sized as a share
+	// of the cell, so they keep their look on every grid
+	THERMO_WIDTH     = float32(0.3)
+	THERMO_BULB      = float32(0.38)
+	PALINDROME_WIDTH = float32(0.18)
+	RENBAN_WIDTH     = float32(0.3)
+	ARROW_CIRCLE     = float32(0.4)
+	ARROW_HEAD       = float32(0.25)
+
+	// Markers sit on the gap between two cells and reach a little way into
+	// each, sized as a share of the cell as well
+	KROPKI_DOT   = float32(0.12)
+	XV_DISC      = float32(0.17)
+	GREATER_SIGN = float32(0.14)
+)
+
+// updateOverlay marks the cells the constraints shade and hands the cells
+// along each constraint line their share of it, and the cells either side
+// of each edge marker the marker
+func (g *Game) updateOverlay() {
+	shape := g.board.Shape()
+	g.shaded = make([]bool, shape.NumCells())
+
+	for idx, cell := range g.cells {
+		if cell != nil {
+			cell.Strokes = nil
+			cell.Discs = nil
+			cell.Marks = nil
+			cell.MarkArea = g.markArea(idx)
+		}
+	}
+
+	for _, constraint := range g.board.Constraints() {
+		overlay := constraint.Overlay(shape)
+
+		for _, idx := range overlay.Shaded {
+			g.shaded[idx] = true
+		}
+
+		for _, line := range overlay.Lines {
+			g.addLine(line, overlay.Style)
+		}
+
+		for _, edge := range overlay.Edges {
+			g.addEdge(edge)
+		}
+	}
+}
+
+// markArea returns the cell grown by half the gap to each neighbor, which is
+// as much of the markers on its sides as the cell draws
+func (g *Game) markArea(idx int) sdl.Rect {
+	shape := g.board.Shape()
+	area := g.cells[idx].Rect
+	row, col := shape.RowCol(idx)
+
+	for side, d := range [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} {
+		r, c := row+d[0], col+d[1]
+
+		if r < 0 || r >= shape.Height() || c < 0 || c >= shape.Width() || shape.Index(r, c) < 0 {
+			continue
+		}
+
+		other := g.cells[shape.Index(r, c)]
+
+		if other == nil {
+			continue
+		}
+
+		// The cell above or left of an odd gap takes its middle pixel
+		gap := sideGap(g.cells[idx], other)
+		half := gap / 2
+
+		switch side {
+		case 0:
+			area.Y -= half
+			area.H += half
+		case 1:
+			area.W += gap - half
+		case 2:
+			area.H += gap - half
+		case 3:
+			area.X -= half
+			area.W += half
+		}
+	}
+
+	return area
+}
+
+// addEdge hands a marker to both cells it sits between, centered on the gap
+// between them
+func (g *Game) addEdge(edge sudoku.Edge) {
+	first, second := g.cells[edge.Cells[0]], g.cells[edge.Cells[1]]
+
+	if first == nil || second == nil {
+		return
+	}
+
+	size := float32(first.Rect.W)
+	from, to := cellCenter(first), cellCenter(second)
+	center := towards(from, to, distance(from, to)/2)
+	mark := Mark{Center: center}
+
+	switch edge.Marker {
+	case sudoku.WhiteDot:
+		mark.Discs = []Disc{
+			{Center: center, Radius: size * KROPKI_DOT, Color: KROPKI_WHITE_COLOR},
+			{Center: center, Radius: size * KROPKI_DOT, Width: thinWidth(size, MARKER_LINE_WIDTH), Color: KROPKI_BLACK_COLOR},
+		}
+	case sudoku.BlackDot:
+		mark.Discs = []Disc{{Center: center, Radius: size * KROPKI_DOT, Color: KROPKI_BLACK_COLOR}}
+	case sudoku.XMark, sudoku.VMark:
+		mark.Discs = []Disc{{Center: center, Radius: size * XV_DISC, Color: XV_DISC_COLOR}}
+		mark.Text = map[sudoku.Marker]string{sudoku.XMark: "X", sudoku.VMark: "V"}[edge.Marker]
+		mark.Color = XV_TEXT_COLOR
+	case sudoku.GreaterMark:
+		// The sign opens towards the first cell, the larger digit, and
+		// points at the second
+		tip := towards(center, to, size*GREATER_SIGN/2)
+		back := towards(center, from, size*GREATER_SIGN/2)
+
+		for _, angle := range []float64{-math.Pi / 4, math.Pi / 4} {
+			mark.Strokes = append(mark.Strokes, Stroke{From: tip, To: rotate(back, tip, angle), Width: thinWidth(size, MARKER_LINE_WIDTH), Color: GREATER_COLOR})
+		}
+	}
+
+	first.Marks = append(first.Marks, mark)
+	second.Marks = append(second.Marks, mark)
+}
+
+// addLine hands each cell along a line the halves of the line reaching its
+// neighbors, along with the marks the style puts at either end
+func (g *Game) addLine(line []int, style sudoku.LineStyle) {
+	if slices.ContainsFunc(line, func(idx int) bool { return g.cells[idx] == nil }) {
+		return
+	}
+
+	size := float32(g.cells[line[0]].Rect.W)
+	color, width := lineLook(style, size)
+
+	for i, idx := range line {
+		cell := g.cells[idx]
+		center := cellCenter(cell)
+
+		// Each half stops midway to the neighbor, in the gap between the cells
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(line) {
+				continue
+			}
+
+			next := cellCenter(g.cells[line[j]])
+			from := center
+
+			// The arrow leaves from the edge of its circle
+			if style == sudoku.ArrowLine && i == 0 {
+				from = towards(center, next, size*ARROW_CIRCLE)
+			}
+
+			cell.Strokes = append(cell.Strokes, Stroke{From: from, To: towards(center, next, distance(center, next)/2), Width: width, Color: color})
+		}
+
+		// Thick lines are rounded where they bend
+		if width > thinWidth(size, CONSTRAINT_LINE_WIDTH) {
+			cell.Discs = append(cell.Discs, Disc{Center: center, Radius: width / 2, Color: color})
+		}
+	}
+
+	first, last := g.cells[line[0]], g.cells[line[len(line)-1]]
+
+	switch style {
+	case sudoku.ThermoLine:
+		first.Discs = append(first.Discs, Disc{Center: cellCenter(first), Radius: size * THERMO_BULB, Color: color})
+	case sudoku.ArrowLine:
+		first.Discs = append(first.Discs, Disc{Center: cellCenter(first), Radius: size * ARROW_CIRCLE, Width: width, Color: color})
+
+		tip := cellCenter(last)
+		back := towards(tip, cellCenter(g.cells[line[len(line)-2]]), size*ARROW_HEAD)
+
+		for _, angle := range []float64{-math.Pi / 4, math.Pi / 4} {
+			last.Strokes = append(last.Strokes, Stroke{From: tip, To: rotate(back, tip, angle), Width: width, Color: color})
+		}
+	}
+}
+
+// lineLook returns the color and width of lines in a style across cells of
+// the given size
+func lineLook(style sudoku.LineStyle, size float32) (sdl.Color, float32) {
+	switch style {
+	case sudoku.ThermoLine:
+		return THERMO_COLOR, size * THERMO_WIDTH
+	case sudoku.ArrowLine:
+		return ARROW_COLOR, thinWidth(size, ARROW_LINE_WIDTH)
+	case sudoku.PalindromeLine:
+		return PALINDROME_COLOR, size * PALINDROME_WIDTH
+	case sudoku.RenbanLine:
+		return RENBAN_COLOR, size * RENBAN_WIDTH
+	default:
+		return CONSTRAINT_LINE_COLOR, thinWidth(size, CONSTRAINT_LINE_WIDTH)
+	}
+}
+
+// thinWidth is the width of a thin line taking share of cells of the given
+// size, kept to at least a pixel
+func thinWidth(size float32, share float32) float32 {
+	return max(1, size*share)
+}
+
+func cellCenter(cell *Cell) sdl.FPoint {
+	return sdl.FPoint{
+		X: float32(cell.Rect.X) + float32(cell.Rect.W)/2,
+		Y: float32(cell.Rect.Y) + float32(cell.Rect.H)/2,
+	}
+}
+
+func distance(from sdl.FPoint, to sdl.FPoint) float32 {
+	return float32(math.Hypot(float64(to.X-from.X), float64(to.Y-from.Y)))
+}
+
+// towards returns the point length pixels from one point in the direction
+// of another
+func towards(from sdl.FPoint, to sdl.FPoint, length float32) sdl.FPoint {
+	full := distance(from, to)
+
+	if full == 0 {
+		return from
+	}
+
+	return sdl.FPoint{
+		X: from.X + (to.X-from.X)*length/full,
+		Y: from.Y + (to.Y-from.Y)*length/full,
+	}
+}
+
+// rotate turns a point about a center by angle radians
+func rotate(point sdl.FPoint, center sdl.FPoint, angle float64) sdl.FPoint {
+	sin, cos := float32(math.Sin(angle)), float32(math.Cos(angle))
+	dx, dy := point.X-center.X, point.Y-center.Y
+
+	return sdl.FPoint{
+		X: center.X + dx*cos - dy*sin,
+		Y: center.Y + dx*sin + dy*cos,
+	}
+}
@@ -0,0 +1,77 @@
+package engine
+
+import "github.com/veandco/go-sdl2/sdl"
+
+var (
+	SELECTED_CELL_COLOR = sdl.Color{R: 0x8C, G: 0xB4, B: 0xE6, A: 0xFF}
+	PEER_CELL_COLOR     = sdl.Color{R: 0xC4, G: 0xCC, B: 0xD8, A: 0xFF}
+	SAME_DIGIT_COLOR    = sdl.Color{R: 0xA8, G: 0xC8, B: 0xF0, A: 0xFF}
+	SAME_NOTE_COLOR     = sdl.Color{R: 0xBC, G: 0xCC, B: 0xE0, A: 0xFF}
+)
+
+// Select moves the cursor to a cell, or clears it with -1, scrolling a
+// layout of grids to bring the cell into view
+func (g *Game) Select(idx int) {
+	g.selected = idx
+	g.scrollTo(idx)
+	g.updateCells()
+}
+
+func (g *Game) Selected() int {
+	return g.selected
+}
+
+// MoveCursor shifts the selection by rows and columns, wrapping at the edges
+// and passing over the holes between the grids of a layout
+func (g *Game) MoveCursor(rows int, cols int) {
+	if g.selected < 0 {
+		g.Select(0)
+		return
+	}
+
+	shape := g.board.Shape()
+	row, col := shape.RowCol(g.selected)
+
+	for range max(shape.Width(), shape.Height()) {
+		row = (row + rows + shape.Height()) % shape.Height()
+		col = (col + cols + shape.Width()) % shape.Width()
+
+		if idx := shape.Index(row, col); idx >= 0 {
+			g.Select(idx)
+			return
+		}
+	}
+}
+
+// highlightDigit is the digit whose placements and notes are highlighted:
+// the one in the selected cell, or else the active palette digit
+func (g *Game) highlightDigit() byte {
+	if g.selected >= 0 && g.board.Value(g.selected) != 0 {
+		return g.board.Value(g.selected)
+	}
+
+	return g.activeDigit
+}
+
+// selectionColor returns the highlight for a cell based on the selection
+func (g *Game) selectionColor(idx int) (sdl.Color, bool) {
+	if idx == g.selected {
+		return SELECTED_CELL_COLOR, true
+	}
+
+	if digit := g.highlightDigit(); digit != 0 && g.Settings.HighlightSameDigit {
+		if g.board.Value(idx) == digit {
+			return SAME_DIGIT_COLOR, true
+		}
+
+		if g.board.Value(idx) == 0 && g.board.Notes(idx).Has(digit) {
+			return SAME_NOTE_COLOR, true
+		}
+	}
+
+	if g.selected >= 0 && g.Settings.HighlightPeers && g.board.Shape().IsPeer(idx, g.selected) {
+		return PEER_CELL_COLOR, true
+	}
+
+	return sdl.Color{}, false
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Input modes decide what clicking a cell does
+const (
+	INPUT_DIGIT = iota
+	INPUT_NOTES
+	INPUT_COLOR
+)
+
+var (
+	INPUT_MODE_NAMES = []string{"Digit", "Notes", "Color"}
+
+	// MARK_COLORS are the highlights a cell cycles through in color mode,
+	// where the first entry stands for no highlight
+	MARK_COLORS = []sdl.Color{
+		CELL_COLOR,
+		{R: 0xF0, G: 0xA0, B: 0xA0, A: 0xFF},
+		{R: 0xA0, G: 0xD0, B: 0xF0, A: 0xFF},
+		{R: 0xB0, G: 0xE8, B: 0xA0, A: 0xFF},
+		{R: 0xE0, G: 0xB0, B: 0xF0, A: 0xFF},
+	}
+)
+
+func (g *Game) SetInputMode(mode int) {
+	g.inputMode = mode
+	g.modeButton.Text = INPUT_MODE_NAMES[mode]
+
+	if mode == INPUT_DIGIT {
+		g.modeButton.SetColor(DIGIT_COLOR)
+	} else {
+		g.modeButton.SetColor(SELECTED_DIGIT_COLOR)
+	}
+}
+
+// ClickCell selects a cell and applies the active digit to it according to
+// the input mode, where holding Shift in digit mode toggles a note instead
+func (g *Game) ClickCell(e *Engine, idx int) {
+	g.Select(idx)
+
+	if g.inputMode == INPUT_COLOR {
+		g.edit("color", func() error {
+			return g.board.SetColor(idx, (g.board.Color(idx)+1)%byte(len(MARK_COLORS)))
+		})
+
+		return
+	}
+
+	if g.activeDigit == 0 {
+		return
+	}
+
+	g.EnterDigit(idx, g.activeDigit, g.inputMode == INPUT_NOTES || e.Modifiers&MOD_SHIFT != 0)
+}
+
+// EnterDigit places a digit in a cell, or toggles it as a note. Entering the
+// digit a cell already holds erases it.
+func (g *Game) EnterDigit(idx int, digit byte, note bool) {
+	if g.board.Given(idx) {
+		return
+	}
+
+	switch {
+	case note:
+		if g.board.Value(idx) == 0 {
+			g.edit("note", func() error {
+				return g.board.ToggleNote(idx, digit)
+			})
+		}
+	case g.board.Value(idx) == digit:
+		g.edit("erase", func() error {
+			return g.board.Erase(idx)
+		})
+	default:
+		g.edit("place", func() error {
+			err := g.board.Set(idx, digit)
+
+			if err == nil && g.Settings.AutoClearNotes {
+				g.board.RemovePeerNotes(idx, digit)
+			}
+
+			return err
+		})
+	}
+}
+
+// EraseCell removes the digit from a cell, or its notes if it has no digit
+func (g *Game) EraseCell(idx int) {
+	if g.board.Given(idx) {
+		return
+	}
+
+	if g.board.Value(idx) != 0 {
+		g.edit("erase", func() error {
+			return g.board.Erase(idx)
+		})
+	} else if !g.board.Notes(idx).Empty() {
+		g.edit("clear notes", func() error {
+			return g.board.SetNotes(idx, 0)
+		})
+	}
+}
+
+// AutoNotes fills every empty cell's notes with its remaining candidates
+func (g *Game) AutoNotes(e *Engine) {
+	g.edit("auto notes", func() error {
+		g.board.FillNotes()
+		return nil
+	})
+}
+
+func (g *Game) Undo(e *Engine) {
+	if _, ok := g.history.Undo(g.board); ok {
+		g.boardChanged()
+	}
+}
+
+func (g *Game) Redo(e *Engine) {
+	if _, ok := g.history.Redo(g.board); ok {
+		g.boardChanged()
+	}
+}
+
+// edit applies a change to the board as one undoable step
+func (g *Game) edit(name string, fn func() error) {
+	err := g.history.Record(g.board, name, fn)
+
+	if err != nil {
+		log.Printf("Error during %s: %s\n", name, err)
+		return
+	}
+
+	g.boardChanged()
+}
+
+func (g *Game) boardChanged() {
+	g.checking = false
+	g.ClearHint()
+	g.updateCells()
+
+	if g.board.Solved() {
+		g.hintLabel.Text = []string{"Solved in " + formatDuration(g.elapsed) + "!", fmt.Sprintf("Hints used: %d", g.hints)}
+		g.finishGame()
+	}
+}
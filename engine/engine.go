@@ -3,14 +3,47 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"strings"
 	"time"
 
+	"main/sudoku"
+
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
 
 var (
 	DEFAULT_DRAW = sdl.Color{R: 0x00, G: 0x00, B: 0x00, A: 0x00}
+
+	// FONT_SIZES are the point sizes every font is loaded in, smallest first
+	FONT_SIZES = []int{8, 10, 14, 18, 24, 36, 48, 72, 96, 108, 120}
+
+	// FONT_FILES maps the name widgets refer to each font by to its file
+	FONT_FILES = map[string]string{
+		// Asap
+		"asap_normal":     "./fonts/asap-font/Asap-M2Pr.ttf",
+		"asap_bold":       "./fonts/asap-font/AsapBold-KMmp.ttf",
+		"asap_bolditalic": "./fonts/asap-font/AsapBoldItalic-2jaK.ttf",
+		"asap_italic":     "./fonts/asap-font/AsapItalic-vyzO.ttf",
+
+		// Courierprime
+		"courierprime_normal":     "./fonts/courier-prime-font/Courierprime-1OVL.ttf",
+		"courierprime_bold":       "./fonts/courier-prime-font/CourierprimeBold-ROxM.ttf",
+		"courierprime_bolditalic": "./fonts/courier-prime-font/CourierprimeBolditalic-BvVV.ttf",
+		"courierprime_italic":     "./fonts/courier-prime-font/CourierprimeItalic-8nVD.ttf",
+
+		// DroidSansMono
+		"droidsansmono_normal": "./fonts/droid-sans-mono-font/DroidSansMono-enMp.ttf",
+
+		// Lotuscoder
+		"lotuscoder_normal": "./fonts/lotuscoder-font/Lotuscoder-0WWrG.ttf",
+		"lotuscoder_bold":   "./fonts/lotuscoder-font/LotuscoderBold-eZZYn.ttf",
+
+		// MantiSansFixed
+		"mantisansfixed_normal": "./fonts/manti-sans-fixed-font/MantiSansFixedDemo-1GDn4.ttf",
+	}
 )
 
 type Engine struct {
@@ -19,7 +52,10 @@ type Engine struct {
 	Fonts    map[int]map[string]*ttf.Font
 
 	InputTransform map[int]byte
+	KeyTransform   map[KeyInput]byte
 	KeyBinds       map[byte][2]func(engine *Engine, args []interface{})
+	RepeatActions  map[byte]bool
+	Modifiers      byte
 
 	Scenes       map[string]Scene
 	CurrentScene Scene
@@ -30,75 +66,43 @@ type Engine struct {
 
 	FrameTime float64
 	LastFrame time.Time
+
+	// Focused is cleared while the window is in the background
+	Focused bool
+
+	// Scale is the number of pixels to a point, which layouts and fonts are
+	// measured in, so the interface can be enlarged on high-DPI displays
+	Scale float64
 }
 
-func (e *Engine) Setup(wind *sdl.Window, rend *sdl.Renderer) error {
+func (e *Engine) Setup(wind *sdl.Window, rend *sdl.Renderer, scale float64) error {
 	*e = Engine{
 		Window:   wind,
 		Renderer: rend,
+		Focused:  true,
+		Scale:    ClampScale(scale),
 	}
 
 	// Initialize fonts for some variety of sizes
-	font_sizes := []int{14, 18, 24, 36, 48, 72, 96, 108, 120}
-
 	e.Fonts = map[int]map[string]*ttf.Font{}
 
-	for _, font_size := range font_sizes {
-		// Check font files for validity
-		font_list := [12]*ttf.Font{}
-		err_list := [12]error{}
-
-		font_list[0], err_list[0] = ttf.OpenFont("./fonts/asap-font/Asap-M2Pr.ttf", font_size)
-		font_list[1], err_list[1] = ttf.OpenFont("./fonts/asap-font/AsapBold-KMmp.ttf", font_size)
-		font_list[2], err_list[2] = ttf.OpenFont("./fonts/asap-font/AsapBoldItalic-2jaK.ttf", font_size)
-		font_list[3], err_list[3] = ttf.OpenFont("./fonts/asap-font/AsapItalic-vyzO.ttf", font_size)
+	for _, font_size := range FONT_SIZES {
+		e.Fonts[font_size] = map[string]*ttf.Font{}
 
-		font_list[4], err_list[4] = ttf.OpenFont("./fonts/courier-prime-font/Courierprime-1OVL.ttf", font_size)
-		font_list[5], err_list[5] = ttf.OpenFont("./fonts/courier-prime-font/CourierprimeBold-ROxM.ttf", font_size)
-		font_list[6], err_list[6] = ttf.OpenFont("./fonts/courier-prime-font/CourierprimeBolditalic-BvVV.ttf", font_size)
-		font_list[7], err_list[7] = ttf.OpenFont("./fonts/courier-prime-font/CourierprimeItalic-8nVD.ttf", font_size)
+		for name, path := range FONT_FILES {
+			font, err := ttf.OpenFont(path, font_size)
 
-		font_list[8], err_list[8] = ttf.OpenFont("./fonts/droid-sans-mono-font/DroidSansMono-enMp.ttf", font_size)
-
-		font_list[9], err_list[9] = ttf.OpenFont("./fonts/lotuscoder-font/Lotuscoder-0WWrG.ttf", font_size)
-		font_list[10], err_list[10] = ttf.OpenFont("./fonts/lotuscoder-font/LotuscoderBold-eZZYn.ttf", font_size)
-
-		font_list[11], err_list[11] = ttf.OpenFont("./fonts/manti-sans-fixed-font/MantiSansFixedDemo-1GDn4.ttf", font_size)
-
-		for _, err := range err_list {
 			if err != nil {
 				return err
 			}
-		}
 
-		// Store font file paths for later use
-		e.Fonts[font_size] = map[string]*ttf.Font{
-			// Asap
-			"asap_normal":     font_list[0],
-			"asap_bold":       font_list[1],
-			"asap_bolditalic": font_list[2],
-			"asap_italic":     font_list[3],
-
-			//Courierprime
-			"courierprime_normal":     font_list[4],
-			"courierprime_bold":       font_list[5],
-			"courierprime_bolditalic": font_list[6],
-			"courierprime_italic":     font_list[7],
-
-			// DroidSansMono
-			"droidsansmono_normal": font_list[8],
-
-			// Lotuscoder
-			"lotuscoder_normal": font_list[9],
-			"lotuscoder_bold":   font_list[10],
-
-			// MantiSansFixed
-			"mantisansfixed_normal": font_list[11],
+			e.Fonts[font_size][name] = font
 		}
 	}
 
 	// Setup input translation maps
 	e.InputTransform = map[int]byte{}
+	e.KeyTransform = map[KeyInput]byte{}
 	e.KeyBinds = map[byte][2]func(*Engine, []interface{}){}
 
 	e.InputTransform[int(sdl.BUTTON_LEFT)] = LEFT_CLICK
@@ -153,33 +157,129 @@ func (e *Engine) Setup(wind *sdl.Window, rend *sdl.Renderer) error {
 		},
 	}
 
-	e.KeyBinds[VERT_SCROLL] = [2]func(*Engine, []interface{}){
-		func(e *Engine, args []interface{}) {
-			fmt.Println("Scroll Up.")
-		},
-		func(e *Engine, args []interface{}) {
-			fmt.Println("Scroll Down.")
-		},
+	// The wheel is forwarded to the scene as presses for up or right and
+	// releases for down or left
+	for _, action := range []byte{VERT_SCROLL, HORIZ_SCROLL} {
+		e.KeyBinds[action] = [2]func(*Engine, []interface{}){
+			func(e *Engine, args []interface{}) {
+				e.CurrentScene.Input(e, action, PRESSED)
+			},
+			func(e *Engine, args []interface{}) {
+				e.CurrentScene.Input(e, action, RELEASED)
+			},
+		}
 	}
 
-	e.KeyBinds[HORIZ_SCROLL] = [2]func(*Engine, []interface{}){
-		func(e *Engine, args []interface{}) {
-			fmt.Println("Scroll Right.")
-		},
+	// Setup keyboard translation, where bound keys are forwarded to the current scene
+	e.RepeatActions = map[byte]bool{}
+
+	key_actions := map[KeyInput]byte{
+		KeyInput{sdl.K_UP, MOD_NONE}:            MOVE_UP,
+		KeyInput{sdl.K_DOWN, MOD_NONE}:          MOVE_DOWN,
+		KeyInput{sdl.K_LEFT, MOD_NONE}:          MOVE_LEFT,
+		KeyInput{sdl.K_RIGHT, MOD_NONE}:         MOVE_RIGHT,
+		KeyInput{sdl.K_BACKSPACE, MOD_NONE}:     BACKSPACE,
+		KeyInput{sdl.K_DELETE, MOD_NONE}:        ERASE,
+		KeyInput{sdl.K_0, MOD_NONE}:             ERASE,
+		KeyInput{sdl.K_ESCAPE, MOD_NONE}:        CANCEL,
+		KeyInput{sdl.K_RETURN, MOD_NONE}:        CONFIRM,
+		KeyInput{sdl.K_KP_ENTER, MOD_NONE}:      CONFIRM,
+		KeyInput{sdl.K_z, MOD_CTRL}:             UNDO,
+		KeyInput{sdl.K_y, MOD_CTRL}:             REDO,
+		KeyInput{sdl.K_z, MOD_CTRL | MOD_SHIFT}: REDO,
+		KeyInput{sdl.K_n, MOD_NONE}:             TOGGLE_NOTES,
+		KeyInput{sdl.K_h, MOD_NONE}:             HINT,
+		KeyInput{sdl.K_c, MOD_CTRL}:             COPY,
+		KeyInput{sdl.K_v, MOD_CTRL}:             PASTE,
+		KeyInput{sdl.K_EQUALS, MOD_NONE}:        ZOOM_IN,
+		KeyInput{sdl.K_PLUS, MOD_NONE}:          ZOOM_IN,
+		KeyInput{sdl.K_EQUALS, MOD_SHIFT}:       ZOOM_IN,
+		KeyInput{sdl.K_KP_PLUS, MOD_NONE}:       ZOOM_IN,
+		KeyInput{sdl.K_MINUS, MOD_NONE}:         ZOOM_OUT,
+		KeyInput{sdl.K_KP_MINUS, MOD_NONE}:      ZOOM_OUT,
+	}
+
+	for digit := 1; digit <= 9; digit++ {
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_0) + digit), MOD_NONE}] = DIGIT_BASE + byte(digit)
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_KP_1) + digit - 1), MOD_NONE}] = DIGIT_BASE + byte(digit)
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_0) + digit), MOD_SHIFT}] = NOTE_BASE + byte(digit)
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_KP_1) + digit - 1), MOD_SHIFT}] = NOTE_BASE + byte(digit)
+	}
+
+	// Digits past 9 are typed with the letters standing for them
+	for digit := 10; digit <= sudoku.MaxSize; digit++ {
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_a) + digit - 10), MOD_NONE}] = DIGIT_BASE + byte(digit)
+		key_actions[KeyInput{sdl.Keycode(int(sdl.K_a) + digit - 10), MOD_SHIFT}] = NOTE_BASE + byte(digit)
+	}
+
+	for input, action := range key_actions {
+		e.KeyTransform[input] = action
+		e.KeyBinds[action] = [2]func(*Engine, []interface{}){
+			func(e *Engine, args []interface{}) {
+				e.CurrentScene.Input(e, action, PRESSED)
+			},
+			func(e *Engine, args []interface{}) {
+				e.CurrentScene.Input(e, action, RELEASED)
+			},
+		}
+	}
+
+	// Fullscreen is handled by the engine rather than any scene
+	e.KeyTransform[KeyInput{sdl.K_F11, MOD_NONE}] = FULLSCREEN
+	e.KeyTransform[KeyInput{sdl.K_RETURN, MOD_ALT}] = FULLSCREEN
+	e.KeyBinds[FULLSCREEN] = [2]func(*Engine, []interface{}){
 		func(e *Engine, args []interface{}) {
-			fmt.Println("Scroll Left.")
+			err := e.ToggleFullscreen()
+
+			if err != nil {
+				log.Printf("Error toggling fullscreen: %s\n", err)
+			}
 		},
+		func(e *Engine, args []interface{}) {},
+	}
+
+	for _, action := range []byte{MOVE_UP, MOVE_DOWN, MOVE_LEFT, MOVE_RIGHT, UNDO, REDO, BACKSPACE, ZOOM_IN, ZOOM_OUT} {
+		e.RepeatActions[action] = true
 	}
 
 	// Setup application scenes
 	e.Scenes = map[string]Scene{}
 
+	// The menu, game, library and statistics are built on top of their
+	// scene files, which must be found, while the other files become scenes
+	// of their own
+	files := LoadSceneFiles()
+
+	menuFile, err := TakeSceneFile(&files, "Main Menu")
+
+	if err != nil {
+		return err
+	}
+
+	gameFile, err := TakeSceneFile(&files, "Game")
+
+	if err != nil {
+		return err
+	}
+
+	libraryFile, err := TakeSceneFile(&files, "Library")
+
+	if err != nil {
+		return err
+	}
+
+	statisticsFile, err := TakeSceneFile(&files, "Statistics")
+
+	if err != nil {
+		return err
+	}
+
 	// Add menu scene to engine
 	menu := &Menu{}
 
 	menu.Setup(e, "Main Menu", nil)
 
-	err := menu.MainMenu(e)
+	err = menu.MainMenu(e, menuFile)
 
 	if err != nil {
 		return err
@@ -190,15 +290,58 @@ func (e *Engine) Setup(wind *sdl.Window, rend *sdl.Renderer) error {
 
 	game.Setup(e, "Game", nil)
 
-	err = game.NewGame()
+	err = game.NewGame(e, gameFile)
+
+	if err != nil {
+		return err
+	}
+
+	// Add library scene to engine
+	library := &Library{}
+
+	library.Setup(e, "Library", nil)
+
+	err = library.NewLibrary(e, libraryFile)
+
+	if err != nil {
+		return err
+	}
+
+	// Add statistics scene to engine
+	statistics := &Statistics{}
+
+	statistics.Setup(e, "Statistics", nil)
+
+	err = statistics.NewStatistics(e, statisticsFile)
 
 	if err != nil {
 		return err
 	}
 
+	// Add the scenes described by scene files, skipping any that fail to
+	// build so a mistake in one does not stop the application
+	for _, file := range files {
+		scene := &FileScene{}
+
+		err = scene.Setup(e, file.Title, nil)
+
+		if err != nil {
+			log.Printf("Error building scene %s: %s\n", file.Title, err)
+			continue
+		}
+
+		err = scene.NewFileScene(e, file)
+
+		if err != nil {
+			log.Printf("Error building scene %s: %s\n", file.Title, err)
+			scene.Delete(e)
+		}
+	}
+
 	// Set and activate menu as starting scene
 	e.CurrentScene = menu
 	*menu.Active() = true
+	menu.Enter(e)
 
 	e.LastFrame = time.Now()
 
@@ -224,6 +367,28 @@ func (e *Engine) ProcessAction(input_id byte, pressed byte, args []interface{})
 	return nil
 }
 
+// ProcessKey translates a keyboard event through the input map, skipping
+// unbound keys and repeats of actions that should not repeat
+func (e *Engine) ProcessKey(key sdl.Keycode, mod uint16, pressed byte, repeat bool) error {
+	e.Modifiers = Modifiers(mod)
+
+	input_id, ok := e.KeyTransform[KeyInput{key, e.Modifiers}]
+
+	if !ok {
+		return nil
+	}
+
+	if repeat && !e.RepeatActions[input_id] {
+		return nil
+	}
+
+	return e.ProcessAction(input_id, pressed, []interface{}{key, mod, repeat})
+}
+
+func (e *Engine) TextInput(text string) {
+	e.CurrentScene.TextInput(e, text)
+}
+
 func (e *Engine) DrawQuad(verts [4]sdl.Vertex, indices [6]int32) error {
 	err := e.Renderer.RenderGeometry(nil, verts[:], indices[:])
 
@@ -234,17 +399,124 @@ func (e *Engine) DrawQuad(verts [4]sdl.Vertex, indices [6]int32) error {
 	return nil
 }
 
-func (e *Engine) DrawText(font_name string, font_size int, lines []string, color sdl.Color, pos sdl.Point) error {
-	sized_fonts, ok := e.Fonts[font_size]
+// DrawThickLine draws a line in any direction as a quad width pixels across
+func (e *Engine) DrawThickLine(from sdl.FPoint, to sdl.FPoint, width float32, color sdl.Color) error {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
 
-	if !ok {
-		return fmt.Errorf("failed to load fonts of size %d", font_size)
+	if length == 0 {
+		return nil
 	}
 
-	font, ok := sized_fonts[font_name]
+	// Step half the width to either side, square to the line
+	nx, ny := -dy/length*width/2, dx/length*width/2
 
-	if !ok {
-		return fmt.Errorf("failed to load font with name %s", font_name)
+	verts := [4]sdl.Vertex{
+		{Position: sdl.FPoint{X: from.X + nx, Y: from.Y + ny}, Color: color},
+		{Position: sdl.FPoint{X: from.X - nx, Y: from.Y - ny}, Color: color},
+		{Position: sdl.FPoint{X: to.X + nx, Y: to.Y + ny}, Color: color},
+		{Position: sdl.FPoint{X: to.X - nx, Y: to.Y - ny}, Color: color},
+	}
+
+	return e.DrawQuad(verts, [6]int32{0, 1, 2, 1, 3, 2})
+}
+
+// CIRCLE_SEGMENTS is how many straight sides approximate a circle
+const CIRCLE_SEGMENTS = 32
+
+// DrawCircle fills a circle as a fan of triangles around its center
+func (e *Engine) DrawCircle(center sdl.FPoint, radius float32, color sdl.Color) error {
+	verts := []sdl.Vertex{{Position: center, Color: color}}
+	indices := []int32{}
+
+	for i := range CIRCLE_SEGMENTS {
+		verts = append(verts, sdl.Vertex{Position: circlePoint(center, radius, i), Color: color})
+		indices = append(indices, 0, int32(i+1), int32((i+1)%CIRCLE_SEGMENTS+1))
+	}
+
+	return e.Renderer.RenderGeometry(nil, verts, indices)
+}
+
+// DrawRing draws the outline of a circle width pixels thick, inside the radius
+func (e *Engine) DrawRing(center sdl.FPoint, radius float32, width float32, color sdl.Color) error {
+	for i := range CIRCLE_SEGMENTS {
+		verts := [4]sdl.Vertex{
+			{Position: circlePoint(center, radius, i), Color: color},
+			{Position: circlePoint(center, radius-width, i), Color: color},
+			{Position: circlePoint(center, radius, i+1), Color: color},
+			{Position: circlePoint(center, radius-width, i+1), Color: color},
+		}
+
+		err := e.DrawQuad(verts, [6]int32{0, 1, 2, 1, 3, 2})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// circlePoint returns corner i of the polygon standing in for a circle
+func circlePoint(center sdl.FPoint, radius float32, i int) sdl.FPoint {
+	angle := 2 * math.Pi * float64(i) / CIRCLE_SEGMENTS
+
+	return sdl.FPoint{
+		X: center.X + radius*float32(math.Cos(angle)),
+		Y: center.Y + radius*float32(math.Sin(angle)),
+	}
+}
+
+// DrawDashedLine draws a horizontal or vertical line as dashes of the given
+// length, spaced so that lines over the same pixels always line up
+func (e *Engine) DrawDashedLine(from sdl.Point, to sdl.Point, dash int32, color sdl.Color) error {
+	if from.X != to.X && from.Y != to.Y {
+		return fmt.Errorf("dashed line from (%d, %d) to (%d, %d) is not straight", from.X, from.Y, to.X, to.Y)
+	}
+
+	horizontal := from.Y == to.Y
+	start, end := from.Y, to.Y
+
+	if horizontal {
+		start, end = from.X, to.X
+	}
+
+	start, end = min(start, end), max(start, end)
+
+	e.Renderer.SetDrawColor(color.R, color.G, color.B, color.A)
+	defer e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
+
+	// Dashes start on multiples of twice their length
+	period := 2 * dash
+
+	for pos := start - ((start%period)+period)%period; pos <= end; pos += period {
+		a, b := max(pos, start), min(pos+dash-1, end)
+
+		if a > b {
+			continue
+		}
+
+		var err error
+
+		if horizontal {
+			err = e.Renderer.DrawLine(a, from.Y, b, from.Y)
+		} else {
+			err = e.Renderer.DrawLine(from.X, a, from.X, b)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) DrawText(font_name string, font_size int, lines []string, color sdl.Color, pos sdl.Point) error {
+	font, err := e.font(font_name, font_size)
+
+	if err != nil {
+		return err
 	}
 
 	for line_num, line := range lines {
@@ -285,10 +557,73 @@ func (e *Engine) DrawText(font_name string, font_size int, lines []string, color
 	return nil
 }
 
+// WrapText splits text into lines no wider than width pixels, breaking at spaces
+func (e *Engine) WrapText(font_name string, font_size int, text string, width int32) ([]string, error) {
+	font, err := e.font(font_name, font_size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{}
+	line := ""
+
+	for _, word := range strings.Fields(text) {
+		candidate := word
+
+		if line != "" {
+			candidate = line + " " + word
+		}
+
+		line_width, _, err := e.GetTextSize(font, candidate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if int32(line_width) > width && line != "" {
+			lines = append(lines, line)
+			line = word
+		} else {
+			line = candidate
+		}
+	}
+
+	return append(lines, line), nil
+}
+
+// WindowRect is the whole of the window, which scenes lay themselves out in
+func (e *Engine) WindowRect() sdl.Rect {
+	width, height := e.Window.GetSize()
+
+	return sdl.Rect{W: width, H: height}
+}
+
+// FontSizeFor picks the largest loaded font size no bigger than size, or
+// the smallest one if none fit
+func FontSizeFor(size int) int {
+	best := FONT_SIZES[0]
+
+	for _, font_size := range FONT_SIZES {
+		if font_size <= size {
+			best = font_size
+		}
+	}
+
+	return best
+}
+
 func (e *Engine) GetTextSize(font *ttf.Font, text string) (int, int, error) {
 	return font.SizeUTF8(text)
 }
 
+// Quit lets the current scene finish up before the application exits
+func (e *Engine) Quit() {
+	if e.CurrentScene != nil {
+		e.CurrentScene.Leave(e)
+	}
+}
+
 func (e *Engine) FreeFonts() {
 	for _, sized_fonts := range e.Fonts {
 		for _, font := range sized_fonts {
@@ -298,21 +633,14 @@ func (e *Engine) FreeFonts() {
 }
 
 func (e *Engine) CenterTextInRect(font_name string, font_size int, lines []string, rect sdl.Rect) (sdl.Point, error) {
-	sized_fonts, ok := e.Fonts[font_size]
+	font, err := e.font(font_name, font_size)
 
-	if !ok {
-		return sdl.Point{}, fmt.Errorf("failed to load font of size %d", font_size)
-	}
-
-	font, ok := sized_fonts[font_name]
-
-	if !ok {
-		return sdl.Point{}, fmt.Errorf("failed to load font with name %s", font_name)
+	if err != nil {
+		return sdl.Point{}, err
 	}
 
 	var line_width int
 	var line_height int
-	var err error
 
 	max_len := 0
 
@@ -352,9 +680,11 @@ func (e *Engine) InsertScene(scene Scene) error {
 
 func (e *Engine) Switch(title string) error {
 	if e.CurrentScene.GetTitle() != title && e.ContainsScene(title) {
+		e.CurrentScene.Leave(e)
 		*e.CurrentScene.Active() = false
 		e.CurrentScene = e.Scenes[title]
 		*e.CurrentScene.Active() = true
+		e.CurrentScene.Enter(e)
 		e.CurrentScene.Hover(e, e.MousePos)
 	} else if e.CurrentScene.GetTitle() == title {
 		return fmt.Errorf("scene with title %s already current", title)
@@ -0,0 +1,288 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// FileScene is a scene built from a SceneFile, whose buttons run the
+// actions the file names. Scenes with behaviour of their own embed it,
+// adding to Actions before building and finding the nodes they drive by
+// name afterwards.
+type FileScene struct {
+	SceneBase
+
+	// Actions are the actions only this scene's buttons can run, checked
+	// before SCENE_ACTIONS
+	Actions map[string]SceneAction
+
+	layout     Layout
+	background sdl.Color
+
+	// named holds the nodes the file gave a name, by that name
+	named map[string]Placeable
+}
+
+func (f *FileScene) Setup(e *Engine, title string, args []interface{}) error {
+	*f = FileScene{}
+
+	err := f.setupBase(e, title)
+
+	if err != nil {
+		return err
+	}
+
+	return e.InsertScene(f)
+}
+
+func (f *FileScene) RenderWidgets(e *Engine) error {
+	if f.background != (sdl.Color{}) {
+		rect := e.WindowRect()
+
+		e.Renderer.SetDrawColor(f.background.R, f.background.G, f.background.B, f.background.A)
+		e.Renderer.FillRect(&rect)
+		e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
+	}
+
+	return f.SceneBase.RenderWidgets(e)
+}
+
+func (f *FileScene) Arrange(e *Engine) {
+	if f.layout != nil {
+		f.layout.Arrange(e.WindowRect(), e.Scale)
+	}
+}
+
+// NewFileScene builds the widgets and layout a scene file describes. Errors
+// name the node at fault by its path from the root of the layout.
+func (f *FileScene) NewFileScene(e *Engine, file SceneFile) error {
+	background, err := ParseColor(file.Background)
+
+	if err != nil {
+		return fmt.Errorf("background: %s", err)
+	}
+
+	f.background = background
+	f.named = map[string]Placeable{}
+
+	root, err := f.build(file.Layout, "layout")
+
+	if err != nil {
+		return err
+	}
+
+	// A widget at the root still needs a container to fill the window
+	layout, ok := root.Placeable.(Layout)
+
+	if !ok {
+		layout = &Anchor{Item: root}
+	}
+
+	f.layout = layout
+	f.Arrange(e)
+
+	return nil
+}
+
+// build makes the item for a node of the layout, along with the widgets
+// and containers below it
+func (f *FileScene) build(node Node, path string) (Item, error) {
+	item := Item{Size: sdl.Point{X: node.Width, Y: node.Height}}
+
+	if node.Width < 0 || node.Height < 0 {
+		return item, fmt.Errorf("%s: size %dx%d is negative", path, node.Width, node.Height)
+	}
+
+	if len(node.Items) > 0 && slices.Contains([]string{"spacer", "area", "label", "button"}, node.Type) {
+		return item, fmt.Errorf("%s: a %s holds no items", path, node.Type)
+	}
+
+	items := []Item{}
+
+	for i, child := range node.Items {
+		built, err := f.build(child, fmt.Sprintf("%s.items[%d]", path, i))
+
+		if err != nil {
+			return item, err
+		}
+
+		items = append(items, built)
+	}
+
+	padding, err := insets(node.Padding)
+
+	if err != nil {
+		return item, fmt.Errorf("%s: %s", path, err)
+	}
+
+	switch node.Type {
+	case "vstack", "hstack":
+		stack := &Stack{Items: items, Horizontal: node.Type == "hstack", Spacing: node.Spacing, Padding: padding}
+
+		stack.Align, err = align("align", node.Align)
+
+		if err == nil {
+			stack.Justify, err = align("justify", node.Justify)
+		}
+
+		item.Placeable = stack
+	case "grid":
+		if node.Columns < 1 {
+			return item, fmt.Errorf("%s: a grid needs at least 1 column", path)
+		}
+
+		item.Placeable = &Grid{Items: items, Columns: node.Columns, Spacing: sdl.Point{X: node.Spacing, Y: node.Spacing}, Padding: padding, Square: node.Square}
+	case "anchor":
+		if len(items) != 1 {
+			return item, fmt.Errorf("%s: an anchor holds 1 item, got %d", path, len(items))
+		}
+
+		anchor := &Anchor{Item: items[0], Padding: padding}
+
+		if len(node.Offset) != 0 && len(node.Offset) != 2 {
+			return item, fmt.Errorf("%s: offset takes 2 values, got %d", path, len(node.Offset))
+		}
+
+		if len(node.Offset) == 2 {
+			anchor.Offset = sdl.Point{X: node.Offset[0], Y: node.Offset[1]}
+		}
+
+		anchor.Horizontal, err = align("horizontal", node.Horizontal)
+
+		if err == nil {
+			anchor.Vertical, err = align("vertical", node.Vertical)
+		}
+
+		item.Placeable = anchor
+	case "spacer":
+		item.Placeable = Spacer{}
+	case "area":
+		item.Placeable = &Area{}
+	case "label", "button":
+		item.Placeable, err = f.buildWidget(node)
+	default:
+		return item, fmt.Errorf("%s: unknown type %q", path, node.Type)
+	}
+
+	if err != nil {
+		return item, fmt.Errorf("%s: %s", path, err)
+	}
+
+	if node.Name != "" {
+		if _, ok := f.named[node.Name]; ok {
+			return item, fmt.Errorf("%s: name %q is already taken", path, node.Name)
+		}
+
+		f.named[node.Name] = item.Placeable
+	}
+
+	return item, nil
+}
+
+// named finds the node a scene file gave a name, which must be of the type
+// the scene's code expects
+func named[T Placeable](f *FileScene, name string) (T, error) {
+	var zero T
+
+	placeable, ok := f.named[name]
+
+	if !ok {
+		return zero, fmt.Errorf("scene %s has no node named %q", f.Title, name)
+	}
+
+	found, ok := placeable.(T)
+
+	if !ok {
+		return zero, fmt.Errorf("node %q of scene %s is a %T, want a %T", name, f.Title, placeable, zero)
+	}
+
+	return found, nil
+}
+
+// buildWidget sets up the label or button a node describes through the
+// widget's generic Setup
+func (f *FileScene) buildWidget(node Node) (Widget, error) {
+	color, err := ParseColor(node.Color)
+
+	if err != nil {
+		return nil, err
+	}
+
+	textColor, err := ParseColor(node.TextColor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Type == "label" {
+		label := &Label{}
+
+		err = label.Setup(f, []interface{}{LabelOptions{
+			Color:     color,
+			Text:      node.Text,
+			TextColor: textColor,
+			FontName:  node.Font,
+			FontSize:  node.FontSize,
+		}})
+
+		return label, err
+	}
+
+	if len(node.Text) > 1 {
+		return nil, fmt.Errorf("a button has 1 line of text, got %d", len(node.Text))
+	}
+
+	text := ""
+
+	if len(node.Text) == 1 {
+		text = node.Text[0]
+	}
+
+	shortcuts := []byte{}
+
+	for _, name := range node.Shortcuts {
+		shortcut, ok := SCENE_SHORTCUTS[name]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown shortcut %q", name)
+		}
+
+		shortcuts = append(shortcuts, shortcut)
+	}
+
+	run := func(e *Engine) error { return nil }
+
+	if node.Action != "" {
+		run, err = parseAction(f.Actions, node.Action)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	button := &Button{}
+
+	err = button.Setup(f, []interface{}{ButtonOptions{
+		Color:     color,
+		Text:      text,
+		TextColor: textColor,
+		FontName:  node.Font,
+		FontSize:  node.FontSize,
+		Shortcuts: shortcuts,
+		Brighten:  true,
+		OnClick: func(e *Engine) {
+			button.BackgroundColor = button.InitBackgroundColor
+
+			err := run(e)
+
+			if err != nil {
+				log.Printf("Error during click for widget %s: %s\n", button.GetWidgetID(), err)
+			}
+		},
+	}})
+
+	return button, err
+}
@@ -1,216 +1,697 @@
 package engine
 
 import (
-	"fmt"
 	"log"
-	"strings"
+	"math"
+	"time"
+
+	"main/generator"
+	"main/logic"
+	"main/solver"
+	"main/sudoku"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type Game struct {
-	Title string
+var (
+	CELL_COLOR       = sdl.Color{R: 0xAF, G: 0xAF, B: 0xAF, A: 0xFF}
+	GIVEN_TEXT_COLOR = sdl.Color{R: 0x03, G: 0x07, B: 0x16, A: 0xFF}
+	ENTRY_TEXT_COLOR = sdl.Color{R: 0x10, G: 0x40, B: 0xC0, A: 0xFF}
+	NOTE_TEXT_COLOR  = sdl.Color{R: 0x30, G: 0x30, B: 0x40, A: 0xFF}
+
+	DIGIT_COLOR          = sdl.Color{R: 0x40, G: 0x60, B: 0x90, A: 0xFF}
+	SELECTED_DIGIT_COLOR = sdl.Color{R: 0x10, G: 0x40, B: 0xC0, A: 0xFF}
+)
 
-	Widgets map[string]Widget
+const DEFAULT_PUZZLE = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
 
-	isActive bool
+const BOX_GAP = int32(5)
 
-	board [81]byte
+type Game struct {
+	FileScene
+
+	board    *sudoku.Board
+	solution sudoku.Grid
+
+	// cells and digits are laid out for shape, the size of the last grid
+	// loaded, with nil cells in the holes of a layout
+	shape  sudoku.Shape
+	cells  []*Cell
+	digits []*Button
+
+	// The scene file places the panel beside gridArea, with palette
+	// holding the digit buttons. square is the largest square centered in
+	// gridArea, which the grid is fitted in, and scale the pixels to a
+	// point it was laid out at.
+	palette  *Grid
+	gridArea *Area
+	square   sdl.Rect
+	scale    float64
+
+	// cellSize is the zoomed size of each cell, from fitSize at the least
+	// zoom, and cellGap and boxGap the space between cells and boxes.
+	// scroll is how far the canvas of a layout is scrolled past the top
+	// left of the grid square.
+	fitSize  int32
+	cellSize int32
+	cellGap  int32
+	boxGap   int32
+	scroll   sdl.Point
+
+	activeDigit byte
+	selected    int
+
+	info       *Label
+	seed       int64
+	difficulty sudoku.Difficulty
+
+	hintLabel *Label
+	hint      hint
+	hints     int
+
+	timerLabel *Label
+	elapsed    time.Duration
+	finished   bool
+
+	modeButton *Button
+	inputMode  int
+
+	history *sudoku.History
+
+	// shaded marks the cells a constraint tints, such as Windoku windows
+	shaded []bool
+
+	mistakeButton *Button
+	checking      bool
+
+	autoClearButton *Button
+	peersButton     *Button
+	sameDigitButton *Button
+
+	Settings Settings
 }
 
 func (g *Game) Setup(e *Engine, title string, args []interface{}) error {
 	*g = Game{}
 
-	_, ok := e.Scenes[title]
+	err := g.setupBase(e, title)
 
-	if ok {
-		return fmt.Errorf("scene with same title already exists: %s", title)
+	if err != nil {
+		return err
 	}
 
-	g.Title = title
+	g.board = sudoku.NewBoard(sudoku.Classic)
 
-	g.Widgets = map[string]Widget{}
+	g.history = sudoku.NewHistory()
 
-	g.isActive = false
+	g.selected = -1
 
-	g.board = [81]byte{}
+	g.Settings, err = LoadSettings()
+
+	if err != nil {
+		log.Printf("Error loading settings: %s\n", err)
+	}
 
 	return e.InsertScene(g)
 }
 
-func (g *Game) Delete(e *Engine) error {
-	return e.DeleteScene(g.Title)
+func (g *Game) RenderWidgets(e *Engine) error {
+	g.tick(e)
+
+	return g.FileScene.RenderWidgets(e)
 }
 
-func (g *Game) GetTitle() string {
-	return g.Title
+func (g *Game) Input(e *Engine, action byte, pressed byte) {
+	if !g.isActive {
+		return
+	}
+
+	for _, widget := range g.Widgets {
+		widget.Input(e, action, pressed)
+	}
+
+	if action == VERT_SCROLL || action == HORIZ_SCROLL {
+		g.Wheel(e, action, pressed)
+		return
+	}
+
+	if pressed != PRESSED {
+		return
+	}
+
+	switch {
+	case action == MOVE_UP:
+		g.MoveCursor(-1, 0)
+	case action == MOVE_DOWN:
+		g.MoveCursor(1, 0)
+	case action == MOVE_LEFT:
+		g.MoveCursor(0, -1)
+	case action == MOVE_RIGHT:
+		g.MoveCursor(0, 1)
+	case action == CANCEL:
+		// Escape clears the selection first, then leaves the game
+		if g.selected >= 0 {
+			g.Select(-1)
+		} else if err := e.Switch("Main Menu"); err != nil {
+			log.Printf("Error leaving game: %s\n", err)
+		}
+	case action == ERASE || action == BACKSPACE:
+		if g.selected >= 0 {
+			g.EraseCell(g.selected)
+		}
+	case action > DIGIT_BASE && int(action-DIGIT_BASE) <= g.board.Shape().Size():
+		if g.selected >= 0 {
+			g.EnterDigit(g.selected, action-DIGIT_BASE, g.inputMode == INPUT_NOTES)
+		} else {
+			g.SelectDigit(action - DIGIT_BASE)
+		}
+	case action > NOTE_BASE && int(action-NOTE_BASE) <= g.board.Shape().Size():
+		if g.selected >= 0 {
+			g.EnterDigit(g.selected, action-NOTE_BASE, true)
+		} else {
+			g.SelectDigit(action - NOTE_BASE)
+			g.SetInputMode(INPUT_NOTES)
+		}
+	case action == ZOOM_IN:
+		g.Zoom(1)
+	case action == ZOOM_OUT:
+		g.Zoom(-1)
+	case action == COPY:
+		if err := g.CopyPuzzle(); err != nil {
+			log.Printf("Error copying puzzle: %s\n", err)
+		}
+	case action == PASTE:
+		if err := e.PastePuzzle(); err != nil {
+			log.Printf("Error pasting puzzle: %s\n", err)
+		}
+	}
 }
 
-func (g *Game) InsertWidget(widget Widget) error {
-	_, ok := g.Widgets[widget.GetWidgetID()]
+// NewGame builds the panel beside the grid from its scene file, giving its
+// buttons the game's actions, then loads the default puzzle. Cells and
+// digit buttons are laid out for each grid size as puzzles load.
+func (g *Game) NewGame(e *Engine, file SceneFile) error {
+	g.Actions = map[string]SceneAction{
+		"hint": simpleAction(g.Hint),
+		"mode": simpleAction(func(e *Engine) {
+			g.SetInputMode((g.inputMode + 1) % len(INPUT_MODE_NAMES))
+		}),
+		"undo":  simpleAction(g.Undo),
+		"redo":  simpleAction(g.Redo),
+		"check": simpleAction(g.CheckBoard),
+		"mistakes": simpleAction(func(e *Engine) {
+			g.SetMistakeMode((g.Settings.MistakeMode + 1) % len(MISTAKE_MODE_NAMES))
+			g.saveSettings()
+		}),
+		"auto_clear": simpleAction(func(e *Engine) {
+			g.Settings.AutoClearNotes = !g.Settings.AutoClearNotes
+			g.saveSettings()
+		}),
+		"peers": simpleAction(func(e *Engine) {
+			g.Settings.HighlightPeers = !g.Settings.HighlightPeers
+			g.saveSettings()
+		}),
+		"same_digit": simpleAction(func(e *Engine) {
+			g.Settings.HighlightSameDigit = !g.Settings.HighlightSameDigit
+			g.saveSettings()
+		}),
+		"autofill": simpleAction(g.AutoNotes),
+	}
+
+	err := g.NewFileScene(e, file)
 
-	if ok {
-		return fmt.Errorf("widget already exists: %s", widget.GetWidgetID())
+	if err != nil {
+		return err
 	}
 
-	g.Widgets[widget.GetWidgetID()] = widget
+	// The grid is fitted as a square into the board, and the digit buttons
+	// into the palette
+	g.gridArea, err = named[*Area](&g.FileScene, "board")
 
-	return nil
+	if err != nil {
+		return err
+	}
+
+	g.palette, err = named[*Grid](&g.FileScene, "palette")
+
+	if err != nil {
+		return err
+	}
+
+	g.info, err = named[*Label](&g.FileScene, "info")
+
+	if err != nil {
+		return err
+	}
+
+	g.hintLabel, err = named[*Label](&g.FileScene, "hint")
+
+	if err != nil {
+		return err
+	}
+
+	g.timerLabel, err = named[*Label](&g.FileScene, "timer")
+
+	if err != nil {
+		return err
+	}
+
+	g.modeButton, err = named[*Button](&g.FileScene, "mode")
+
+	if err != nil {
+		return err
+	}
+
+	g.mistakeButton, err = named[*Button](&g.FileScene, "mistakes")
+
+	if err != nil {
+		return err
+	}
+
+	g.autoClearButton, err = named[*Button](&g.FileScene, "auto_clear")
+
+	if err != nil {
+		return err
+	}
+
+	g.peersButton, err = named[*Button](&g.FileScene, "peers")
+
+	if err != nil {
+		return err
+	}
+
+	g.sameDigitButton, err = named[*Button](&g.FileScene, "same_digit")
+
+	if err != nil {
+		return err
+	}
+
+	g.mistakeButton.Text = MISTAKE_MODE_NAMES[g.Settings.MistakeMode]
+	g.updateSettings()
+	g.Arrange(e)
+
+	givens, err := sudoku.ParseGrid(sudoku.Classic, DEFAULT_PUZZLE)
+
+	if err != nil {
+		return err
+	}
+
+	return g.LoadPuzzle(sudoku.Classic, givens)
 }
 
-func (g *Game) RenderWidgets(e *Engine) error {
-	for _, widget := range g.Widgets {
-		err := widget.Draw(e)
+// Arrange lays the game out for the window, fitting the grid to the new
+// square and resizing the digits to their buttons
+func (g *Game) Arrange(e *Engine) {
+	g.scale = e.Scale
+	g.FileScene.Arrange(e)
 
-		if err != nil {
-			return err
-		}
+	area := g.gridArea.Rect
+	side := min(area.W, area.H)
+	g.square = sdl.Rect{X: area.X + (area.W-side)/2, Y: area.Y + (area.H-side)/2, W: side, H: side}
+
+	for _, button := range g.digits {
+		button.FontSize = g.fontSize(button.Rect.W / 2)
 	}
 
-	return nil
+	if g.cells == nil {
+		return
+	}
+
+	g.fitCells()
+	g.viewChanged()
 }
 
-func (g *Game) ContainsWidget(title string) bool {
-	_, ok := g.Widgets[title]
+// fontSize picks the font size for text about pixels tall at the scale the
+// game is laid out at
+func (g *Game) fontSize(pixels int32) int {
+	return FontSizeFor(int(math.Round(float64(pixels) / g.scale)))
+}
 
-	return ok
+// fitCells sizes the cells and the space between them so the grid fills
+// the square whatever its size. A layout of grids starts again from the
+// least zoom.
+func (g *Game) fitCells() {
+	g.fitSize, g.cellSize, g.cellGap, g.boxGap = g.cellSizes(g.shape)
 }
 
-func (g *Game) DeleteWidget(widgetID string) error {
-	if g.ContainsWidget(widgetID) {
-		delete(g.Widgets, widgetID)
-	} else {
-		return fmt.Errorf("no widget exists with ID: %s", widgetID)
-	}
+// cellSizes works out the size of cell that fits a shape in the square, the
+// size its cells start at and the space between cells and between boxes. A
+// layout of grids keeps its cells big enough to play in, scrolling when its
+// canvas overflows the square.
+func (g *Game) cellSizes(shape sudoku.Shape) (int32, int32, int32, int32) {
+	size := int32(max(shape.Width(), shape.Height()))
+	stacks := int32(shape.Width() / shape.BoxWidth)
+	bands := int32(shape.Height() / shape.BoxHeight)
 
-	// Defragment widgets by compressing ID values
-	seen_widget_types := map[string]int{}
-	widget_buckets := map[string][]Widget{}
+	// Larger grids close up the space between cells
+	gap := scaled(min(10, 90/size), g.scale)
 
-	for id, widget := range g.Widgets {
-		base_name := strings.Split(id, "_")[0]
+	// Jigsaw regions are marked with borders rather than spacing boxes apart
+	boxGap := scaled(BOX_GAP, g.scale)
 
-		seen_widget_types[base_name]++
-		widget_buckets[base_name] = append(widget_buckets[base_name], widget)
+	if shape.Jigsaw() {
+		boxGap = 0
 	}
 
-	for base_name, widget_bucket := range widget_buckets {
-		for i := range seen_widget_types[base_name] {
-			*widget_bucket[i].ID() = base_name + fmt.Sprintf("_%d", i)
-		}
+	fitSize := max(1, (g.square.W-(size-1)*gap-(max(stacks, bands)-1)*boxGap)/size)
+	cellSize := fitSize
+
+	if shape.Multi() {
+		cellSize = max(fitSize, scaled(MULTI_CELL_SIZE, g.scale))
 	}
 
-	return nil
+	return fitSize, cellSize, gap, boxGap
 }
 
-func (g *Game) GetWidgetIDs() []string {
-	widgetIDs := []string{}
+// gridWidgets is the cells and digit buttons of a shape, built before they
+// replace those on screen
+type gridWidgets struct {
+	shape  sudoku.Shape
+	cells  []*Cell
+	digits []*Button
+}
 
-	for _, widget := range g.Widgets {
-		widgetIDs = append(widgetIDs, widget.GetWidgetID())
+// remove takes the cells and digit buttons out of the scene
+func (w gridWidgets) remove(g *Game) error {
+	for _, cell := range w.cells {
+		if cell == nil {
+			continue
+		}
+
+		err := cell.Delete(g)
+
+		if err != nil {
+			return err
+		}
 	}
 
-	return widgetIDs
-}
+	for _, button := range w.digits {
+		if button == nil {
+			continue
+		}
+
+		err := button.Delete(g)
 
-func (g *Game) Hover(e *Engine, pos sdl.Point) {
-	if g.isActive {
-		for _, widget := range g.Widgets {
-			widget.Hover(e, pos)
+		if err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
-func (g *Game) Click(e *Engine, pos sdl.Point) {
-	if g.isActive {
-		for _, widget := range g.Widgets {
-			widget.Click(e, pos)
+// buildGrid makes the cells and digit buttons for a shape, sized by
+// cellSizes. If any fails, those already made are removed again, so the
+// grid on screen is left as it was.
+func (g *Game) buildGrid(shape sudoku.Shape) (gridWidgets, error) {
+	grid := gridWidgets{
+		shape:  shape,
+		cells:  make([]*Cell, shape.NumCells()),
+		digits: make([]*Button, shape.Size()),
+	}
+
+	err := g.addGridWidgets(grid)
+
+	if err != nil {
+		if removeErr := grid.remove(g); removeErr != nil {
+			log.Printf("Error removing cells: %s\n", removeErr)
 		}
+
+		return gridWidgets{}, err
 	}
-}
 
-func (g *Game) Active() *bool {
-	return &g.isActive
+	return grid, nil
 }
 
-func (g *Game) NewGame() error {
+// addGridWidgets fills in the cells and digit buttons of a grid
+func (g *Game) addGridWidgets(grid gridWidgets) error {
+	shape := grid.shape
 	buttonFont := "lotuscoder_normal"
-	buttonFontSize := 24
-
-	// Add 9x9 grid of buttons to game scene
-	cellSize := int32(50)
-
-	for row := 0; row < 9; row++ {
-		for col := 0; col < 9; col++ {
-			x_offs := int32(col)*(cellSize+10) + int32(col/3)*5
-			y_offs := int32(row)*(cellSize+10) + int32(row/3)*5
-
-			button := &Button{}
-
-			err := button.Setup(g, []interface{}{
-				sdl.Point{X: 10 + x_offs, Y: 10 + y_offs},
-				sdl.Point{X: cellSize, Y: cellSize},
-				sdl.Color{R: 0xAF, G: 0xAF, B: 0xAF, A: 0xFF},
-				"",
-				sdl.Color{R: 0x03, G: 0x07, B: 0x16, A: 0xFF},
-				buttonFont, buttonFontSize,
-				func(e *Engine) {
-					button.BackgroundColor = sdl.Color{
-						R: uint8(min(uint16(0xCF), uint16(button.BackgroundColor.R)+uint16(0x66))),
-						G: uint8(min(uint16(0xCF), uint16(button.BackgroundColor.G)+uint16(0x66))),
-						B: uint8(min(uint16(0xCF), uint16(button.BackgroundColor.B)+uint16(0x66))),
-						A: button.BackgroundColor.A,
-					}
-				},
-				func(e *Engine) {
-					button.BackgroundColor = button.InitBackgroundColor
-				},
-				func(e *Engine) {
-					fmt.Printf("Clicked %s -> (%d, %d)\n", button.GetWidgetID(), row, col)
+	_, cellSize, _, _ := g.cellSizes(shape)
+
+	for row := range shape.Height() {
+		for col := range shape.Width() {
+			idx := shape.Index(row, col)
+
+			// Holes in a layout are left without a cell
+			if idx < 0 {
+				continue
+			}
+
+			cell := &Cell{}
+
+			// Cells are put in place by placeCells once they all exist
+			err := cell.SetupWith(g, CellOptions{
+				ButtonOptions: ButtonOptions{
+					Size:      sdl.Point{X: cellSize, Y: cellSize},
+					Color:     CELL_COLOR,
+					TextColor: GIVEN_TEXT_COLOR,
+					FontName:  buttonFont,
+					FontSize:  g.fontSize(cellSize / 2),
+					Brighten:  true,
+					OnClick: func(e *Engine) {
+						g.ClickCell(e, idx)
+					},
 				},
+
+				// Notes are laid out like the cells of a box
+				NoteColumns:  shape.BoxWidth,
+				NoteColor:    NOTE_TEXT_COLOR,
+				NoteFontSize: g.fontSize(cellSize / int32(shape.BoxHeight)),
 			})
 
 			if err != nil {
 				return err
 			}
+
+			cell.CageColor = CAGE_COLOR
+			cell.RegionColor = REGION_COLOR
+
+			grid.cells[idx] = cell
 		}
 	}
 
-	// Add back button to game scene
-	back := &Button{}
+	for digit := byte(1); int(digit) <= shape.Size(); digit++ {
+		button := &Button{}
 
-	err := back.Setup(g, []interface{}{
-		sdl.Point{X: 9*cellSize + 11*10 + 5, Y: 8*cellSize + 10*10},
-		sdl.Point{X: 2*cellSize + 10, Y: cellSize},
-		sdl.Color{R: 0xDF, G: 0x10, B: 0x10, A: 0xFF},
-		"Back",
-		sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
-		buttonFont, buttonFontSize,
-		func(e *Engine) {
-			back.BackgroundColor = sdl.Color{
-				R: uint8(min(uint16(0xCF), uint16(back.BackgroundColor.R)+uint16(0x66))),
-				G: uint8(min(uint16(0xCF), uint16(back.BackgroundColor.G)+uint16(0x66))),
-				B: uint8(min(uint16(0xCF), uint16(back.BackgroundColor.B)+uint16(0x66))),
-				A: back.BackgroundColor.A,
-			}
-		},
-		func(e *Engine) {
-			back.BackgroundColor = back.InitBackgroundColor
-		},
-		func(e *Engine) {
-			err := e.Switch("Main Menu")
+		err := button.SetupWith(g, ButtonOptions{
+			Color:     DIGIT_COLOR,
+			Text:      sudoku.DigitName(digit),
+			TextColor: sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FontName:  buttonFont,
+			Brighten:  true,
+			OnClick: func(e *Engine) {
+				g.SelectDigit(digit)
+			},
+		})
 
-			if err != nil {
-				log.Fatalf("Error during click for widget %s: %s\n", back.GetWidgetID(), err)
-			}
+		if err != nil {
+			return err
+		}
+
+		grid.digits[digit-1] = button
+	}
+
+	return nil
+}
+
+// layoutGrid swaps the cells and digit buttons on screen for a grid built
+// by buildGrid. A layout of grids places its cells on a canvas that may be
+// larger than the square.
+func (g *Game) layoutGrid(grid gridWidgets) {
+	// The old widgets only fail to go if already gone
+	err := gridWidgets{cells: g.cells, digits: g.digits}.remove(g)
+
+	if err != nil {
+		log.Printf("Error removing cells: %s\n", err)
+	}
+
+	g.shape = grid.shape
+	g.cells = grid.cells
+	g.digits = grid.digits
+	g.scroll = sdl.Point{}
+	g.fitCells()
+	g.placeCells()
+
+	// Add digit palette to the right of the grid, shaped like a box
+	g.palette.Columns = grid.shape.BoxWidth
+	g.palette.Items = nil
+
+	for _, button := range g.digits {
+		g.palette.Items = append(g.palette.Items, Fill(button))
+	}
+
+	g.palette.Arrange(g.palette.Rect, g.scale)
+
+	for _, button := range g.digits {
+		button.FontSize = g.fontSize(button.Rect.W / 2)
+	}
+}
+
+// NewPuzzle generates a fresh puzzle from the options and starts playing it
+func (g *Game) NewPuzzle(opts generator.Options) error {
+	puzzle, err := generator.Generate(opts)
+
+	if err != nil {
+		return err
+	}
+
+	return g.Play(puzzle)
+}
+
+// Play starts a generated puzzle, counting it towards the statistics
+func (g *Game) Play(puzzle generator.Puzzle) error {
+	err := g.LoadVariant(puzzle.Shape, puzzle.Givens, sudoku.Variant{Cages: puzzle.Cages, Constraints: puzzle.Constraints})
+
+	if err != nil {
+		return err
+	}
+
+	g.seed = puzzle.Seed
+	g.difficulty = puzzle.Difficulty
+	g.updateInfo()
+	g.startGame()
+
+	return nil
+}
+
+func (g *Game) LoadPuzzle(shape sudoku.Shape, givens sudoku.Grid) error {
+	return g.LoadKiller(shape, givens, nil)
+}
+
+// LoadKiller starts a puzzle whose cells are also grouped into Killer cages,
+// or a classic puzzle when there are none
+func (g *Game) LoadKiller(shape sudoku.Shape, givens sudoku.Grid, cages []sudoku.Cage) error {
+	return g.LoadVariant(shape, givens, sudoku.Variant{Cages: cages})
+}
+
+// LoadVariant starts a puzzle with the cages and constraints of a variant.
+// The board, and the cells of a new shape, are built before anything on
+// screen changes, so a puzzle that fails to load leaves the game as it was.
+func (g *Game) LoadVariant(shape sudoku.Shape, givens sudoku.Grid, variant sudoku.Variant) error {
+	board, err := sudoku.FromGrid(shape, givens)
+
+	if err != nil {
+		return err
+	}
+
+	err = board.SetCages(variant.Cages)
+
+	if err != nil {
+		return err
+	}
 
-			back.BackgroundColor = back.InitBackgroundColor
-		},
-	})
+	err = board.SetConstraints(variant.Constraints)
 
 	if err != nil {
-		return nil
+		return err
 	}
 
+	// Only accept puzzles with exactly one solution
+	solution, err := solver.VariantSolution(shape, givens, variant)
+
+	if err != nil {
+		return err
+	}
+
+	if shape != g.shape {
+		grid, err := g.buildGrid(shape)
+
+		if err != nil {
+			return err
+		}
+
+		g.layoutGrid(grid)
+	}
+
+	g.board = board
+	g.solution = solution
+	g.seed = 0
+	g.difficulty = logic.GradeVariant(shape, givens, variant)
+	g.updateInfo()
+	g.updateCages()
+	g.updateRegions()
+	g.updateOverlay()
+	g.history = sudoku.NewHistory()
+	g.checking = false
+	g.hints = 0
+	g.elapsed = 0
+	g.finished = false
+	g.ClearHint()
+	g.SetInputMode(INPUT_DIGIT)
+	g.selected = -1
+	g.SelectDigit(0)
+	g.updateCells()
+
 	return nil
 }
+
+func (g *Game) Board() *sudoku.Board {
+	return g.board
+}
+
+// SelectDigit picks the digit placed by clicking a cell, or clears it with 0
+func (g *Game) SelectDigit(digit byte) {
+	if digit == g.activeDigit {
+		digit = 0
+	}
+
+	g.activeDigit = digit
+
+	for i, button := range g.digits {
+		if byte(i+1) == digit {
+			button.SetColor(SELECTED_DIGIT_COLOR)
+		} else {
+			button.SetColor(DIGIT_COLOR)
+		}
+	}
+
+	g.updateCells()
+}
+
+// updateCells copies the board state into the cell widgets
+func (g *Game) updateCells() {
+	mistakes := g.mistakes()
+
+	for idx, cell := range g.cells {
+		if cell == nil {
+			continue
+		}
+
+		value := g.board.Value(idx)
+
+		if value == 0 {
+			cell.Text = ""
+		} else {
+			cell.Text = sudoku.DigitName(value)
+		}
+
+		cell.Notes = make([]string, g.shape.Size())
+
+		for _, digit := range g.board.Notes(idx).Digits() {
+			cell.Notes[digit-1] = sudoku.DigitName(digit)
+		}
+
+		if mistakes[idx] {
+			cell.TextColor = MISTAKE_TEXT_COLOR
+		} else if g.board.Given(idx) {
+			cell.TextColor = GIVEN_TEXT_COLOR
+		} else {
+			cell.TextColor = ENTRY_TEXT_COLOR
+		}
+
+		if color, ok := g.hintColor(idx); ok {
+			cell.SetColor(color)
+		} else if idx != g.selected && mistakes[idx] {
+			cell.SetColor(MISTAKE_CELL_COLOR)
+		} else if color, ok := g.selectionColor(idx); ok {
+			cell.SetColor(color)
+		} else if mark := g.board.Color(idx); mark != 0 {
+			cell.SetColor(MARK_COLORS[mark])
+		} else if g.shaded[idx] {
+			cell.SetColor(SHADED_CELL_COLOR)
+		} else {
+			cell.SetColor(CELL_COLOR)
+		}
+	}
+}
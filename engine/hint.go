@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"main/logic"
+	"main/sudoku"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	HINT_NONE = iota
+	HINT_HOUSE
+	HINT_CELLS
+	HINT_ANSWER
+)
+
+var (
+	HINT_HOUSE_COLOR  = sdl.Color{R: 0xC8, G: 0xC8, B: 0x8C, A: 0xFF}
+	HINT_CELL_COLOR   = sdl.Color{R: 0xE8, G: 0xD0, B: 0x60, A: 0xFF}
+	HINT_TARGET_COLOR = sdl.Color{R: 0x70, G: 0xC0, B: 0x70, A: 0xFF}
+	HINT_ERROR_COLOR  = sdl.Color{R: 0xE0, G: 0x70, B: 0x70, A: 0xFF}
+)
+
+// hint is the deduction currently being revealed, one level per Hint click
+type hint struct {
+	step  logic.Step
+	level int
+
+	// wrong lists player entries that disagree with the solution, which are
+	// pointed out instead of a deduction
+	wrong []int
+}
+
+// Hint reveals the next level of the current hint, finding a new one if needed
+func (g *Game) Hint(e *Engine) {
+	if g.board.Solved() {
+		return
+	}
+
+	if g.hint.level == HINT_NONE || g.hint.level == HINT_ANSWER {
+		g.hint = g.findHint()
+		g.hints++
+	} else {
+		g.hint.level++
+	}
+
+	err := g.setHintText(e, g.hintText())
+
+	if err != nil {
+		log.Printf("Error showing hint: %s\n", err)
+	}
+
+	g.updateCells()
+}
+
+func (g *Game) ClearHint() {
+	g.hint = hint{}
+
+	if g.hintLabel != nil {
+		g.hintLabel.Text = []string{""}
+	}
+}
+
+func (g *Game) findHint() hint {
+	wrong := []int{}
+
+	for idx := range g.solution {
+		value := g.board.Value(idx)
+
+		if value != 0 && value != g.solution[idx] {
+			wrong = append(wrong, idx)
+		}
+	}
+
+	if len(wrong) > 0 {
+		return hint{level: HINT_CELLS, wrong: wrong}
+	}
+
+	step, ok := logic.NextStep(logic.NewVariantState(g.board.Shape(), g.board.Grid(), g.board.Variant()))
+
+	if !ok {
+		// Fall back to revealing a cell from the solution
+		for idx := range g.solution {
+			if g.board.Value(idx) == 0 {
+				step = logic.Step{
+					Cells:      []int{idx},
+					Placements: []logic.Placement{{Cell: idx, Digit: g.solution[idx]}},
+				}
+				break
+			}
+		}
+
+		return hint{step: step, level: HINT_ANSWER}
+	}
+
+	return hint{step: step, level: HINT_HOUSE}
+}
+
+func (g *Game) hintText() string {
+	if len(g.hint.wrong) > 0 {
+		return fmt.Sprintf("%d entries do not match the solution", len(g.hint.wrong))
+	}
+
+	step := g.hint.step
+
+	switch g.hint.level {
+	case HINT_HOUSE:
+		return "Look at the highlighted area"
+	case HINT_CELLS:
+		if step.Digits.Empty() {
+			return "Look at the highlighted cells"
+		}
+
+		digits := []string{}
+
+		for _, digit := range step.Digits.Digits() {
+			digits = append(digits, sudoku.DigitName(digit))
+		}
+
+		return fmt.Sprintf("Look at [%s] in the highlighted cells", strings.Join(digits, " "))
+	case HINT_ANSWER:
+		if len(step.Placements) == 1 && len(step.Eliminations) == 0 && len(step.Houses) == 0 && step.Digits.Empty() {
+			placement := step.Placements[0]
+			return fmt.Sprintf("Reveal: %s=%s", g.board.Shape().CellName(placement.Cell), sudoku.DigitName(placement.Digit))
+		}
+
+		return step.Describe(g.board.Shape(), g.board.Constraints())
+	}
+
+	return ""
+}
+
+func (g *Game) setHintText(e *Engine, text string) error {
+	lines, err := e.WrapText(g.hintLabel.FontName, g.hintLabel.FontSize, text, g.hintLabel.Rect.W-10)
+
+	if err != nil {
+		return err
+	}
+
+	g.hintLabel.Text = lines
+
+	return nil
+}
+
+// hintColor returns the highlight for a cell at the current hint level
+func (g *Game) hintColor(idx int) (sdl.Color, bool) {
+	if slices.Contains(g.hint.wrong, idx) {
+		return HINT_ERROR_COLOR, true
+	}
+
+	step := g.hint.step
+
+	if g.hint.level >= HINT_ANSWER {
+		for _, placement := range step.Placements {
+			if placement.Cell == idx {
+				return HINT_TARGET_COLOR, true
+			}
+		}
+
+		for _, elimination := range step.Eliminations {
+			if elimination.Cell == idx {
+				return HINT_TARGET_COLOR, true
+			}
+		}
+	}
+
+	if g.hint.level >= HINT_CELLS && slices.Contains(step.Cells, idx) {
+		return HINT_CELL_COLOR, true
+	}
+
+	if g.hint.level >= HINT_HOUSE {
+		for _, house := range hintHouses(g.board.Shape(), step) {
+			if slices.Contains(g.board.Houses()[house], idx) {
+				return HINT_HOUSE_COLOR, true
+			}
+		}
+	}
+
+	return sdl.Color{}, false
+}
+
+// hintHouses returns the houses a step works in, falling back to the box of
+// its first cell for steps that are not tied to a house
+func hintHouses(shape sudoku.Shape, step logic.Step) []int {
+	if len(step.Houses) > 0 {
+		return step.Houses
+	}
+
+	if len(step.Cells) > 0 {
+		return []int{shape.BoxHouse(shape.BoxOf(step.Cells[0]))}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"fmt"
+
+	"main/formats"
+	"main/sudoku"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// OpenPuzzle loads a puzzle read from a file or the clipboard, keeping any
+// digits placed and pencil marks it carries as entries and notes
+func (g *Game) OpenPuzzle(puzzle formats.Puzzle) error {
+	err := g.LoadPuzzle(puzzle.Shape, puzzle.Givens)
+
+	if err != nil {
+		return err
+	}
+
+	for idx, value := range puzzle.Entries {
+		if value == 0 || g.board.Given(idx) {
+			continue
+		}
+
+		err = g.board.Set(idx, value)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if puzzle.HasNotes {
+		for idx := range puzzle.Notes {
+			if g.board.Value(idx) != 0 {
+				continue
+			}
+
+			err = g.board.SetNotes(idx, puzzle.Notes[idx])
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if puzzle.Name != "" {
+		g.info.Text = append([]string{puzzle.Name}, g.info.Text...)
+	}
+
+	g.updateCells()
+	g.startGame()
+
+	return nil
+}
+
+// ExportPuzzle returns the givens with the player's entries and notes, so
+// pencil mark grids keep them
+func (g *Game) ExportPuzzle() formats.Puzzle {
+	puzzle := formats.Puzzle{
+		Shape:      g.board.Shape(),
+		Givens:     g.board.Givens(),
+		Notes:      make([]sudoku.Candidates, g.board.Shape().NumCells()),
+		Entries:    sudoku.NewGrid(g.board.Shape()),
+		HasNotes:   true,
+		Difficulty: g.difficulty.String(),
+	}
+
+	for idx := range puzzle.Notes {
+		if g.board.Given(idx) {
+			continue
+		}
+
+		puzzle.Entries[idx] = g.board.Value(idx)
+		puzzle.Notes[idx] = g.board.Notes(idx)
+	}
+
+	return puzzle
+}
+
+// CopyPuzzle puts the givens on the clipboard in the line format
+func (g *Game) CopyPuzzle() error {
+	text, err := formats.WriteString(formats.FormatLine, []formats.Puzzle{g.ExportPuzzle()})
+
+	if err != nil {
+		return err
+	}
+
+	return sdl.SetClipboardText(text)
+}
+
+// OpenFile reads the first puzzle in a file and starts it in the game scene
+func (e *Engine) OpenFile(path string) error {
+	puzzles, err := formats.ReadFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	return e.openPuzzle(puzzles[0])
+}
+
+// PastePuzzle starts the puzzle on the clipboard in the game scene
+func (e *Engine) PastePuzzle() error {
+	text, err := sdl.GetClipboardText()
+
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := formats.ReadString(text)
+
+	if err != nil {
+		return err
+	}
+
+	return e.openPuzzle(puzzles[0])
+}
+
+func (e *Engine) openPuzzle(puzzle formats.Puzzle) error {
+	game, ok := e.Scenes["Game"].(*Game)
+
+	if !ok {
+		return fmt.Errorf("no game scene to open puzzle in")
+	}
+
+	err := game.OpenPuzzle(puzzle)
+
+	if err != nil {
+		return err
+	}
+
+	if e.CurrentScene == Scene(game) {
+		return nil
+	}
+
+	return e.Switch("Game")
+}
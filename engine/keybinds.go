@@ -1,5 +1,7 @@
 package engine
 
+import "github.com/veandco/go-sdl2/sdl"
+
 const (
 	PRESSED  = byte(0)
 	RELEASED = byte(1)
@@ -9,4 +11,64 @@ const (
 	MIDDLE_CLICK = byte(2)
 	VERT_SCROLL  = byte(3)
 	HORIZ_SCROLL = byte(4)
+
+	MOVE_UP      = byte(5)
+	MOVE_DOWN    = byte(6)
+	MOVE_LEFT    = byte(7)
+	MOVE_RIGHT   = byte(8)
+	ERASE        = byte(9)
+	CANCEL       = byte(10)
+	CONFIRM      = byte(11)
+	UNDO         = byte(12)
+	REDO         = byte(13)
+	TOGGLE_NOTES = byte(14)
+	HINT         = byte(15)
+	COPY         = byte(16)
+	PASTE        = byte(17)
+	BACKSPACE    = byte(18)
+	ZOOM_IN      = byte(19)
+	ZOOM_OUT     = byte(20)
+	FULLSCREEN   = byte(21)
+
+	// DIGIT_BASE+d enters digit d and NOTE_BASE+d toggles it as a note
+	DIGIT_BASE = byte(32)
+	NOTE_BASE  = byte(64)
 )
+
+// Modifier flags, folded so left and right keys are treated alike
+const (
+	MOD_NONE  = byte(0)
+	MOD_CTRL  = byte(1)
+	MOD_SHIFT = byte(2)
+	MOD_ALT   = byte(4)
+	MOD_GUI   = byte(8)
+)
+
+// KeyInput is a key pressed with a set of MOD_ flags, as bound in
+// Engine.KeyTransform
+type KeyInput struct {
+	Key  sdl.Keycode
+	Mods byte
+}
+
+func Modifiers(mod uint16) byte {
+	mods := MOD_NONE
+
+	if mod&sdl.KMOD_CTRL != 0 {
+		mods |= MOD_CTRL
+	}
+
+	if mod&sdl.KMOD_SHIFT != 0 {
+		mods |= MOD_SHIFT
+	}
+
+	if mod&sdl.KMOD_ALT != 0 {
+		mods |= MOD_ALT
+	}
+
+	if mod&sdl.KMOD_GUI != 0 {
+		mods |= MOD_GUI
+	}
+
+	return mods
+}
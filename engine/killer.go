@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var CAGE_COLOR = sdl.Color{R: 0x20, G: 0x20, B: 0x30, A: 0xFF}
+
+// updateInfo shows the difficulty and variant of the puzzle, and the seed of
+// generated ones
+func (g *Game) updateInfo() {
+	title := g.difficulty.String()
+
+	if g.board.Shape().Jigsaw() {
+		title += " Jigsaw"
+	}
+
+	if g.board.Shape().Multi() {
+		title += " " + g.board.Shape().Layout
+	}
+
+	if name := g.board.Variant().Name(); name != "" {
+		title += " " + name
+	}
+
+	g.info.Text = []string{title}
+
+	if g.seed != 0 {
+		g.info.Text = append(g.info.Text, fmt.Sprintf("Seed %d", g.seed))
+	}
+}
+
+// updateCages outlines the cages of the board on the cells and writes each
+// sum in the corner of its first cell
+func (g *Game) updateCages() {
+	for idx, cell := range g.cells {
+		if cell == nil {
+			continue
+		}
+
+		cell.CageEdges = [4]bool{}
+		cell.CageGaps = [4]int32{}
+		cell.Corner = ""
+
+		cage := g.board.CageOf(idx)
+
+		if cage < 0 {
+			continue
+		}
+
+		shape := g.board.Shape()
+		row, col := shape.RowCol(idx)
+
+		for side, d := range [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} {
+			r, c := row+d[0], col+d[1]
+
+			if r < 0 || r >= shape.Size() || c < 0 || c >= shape.Size() {
+				cell.CageEdges[side] = true
+				continue
+			}
+
+			next := shape.Index(r, c)
+			cell.CageEdges[side] = g.board.CageOf(next) != cage
+
+			if other := g.cells[next]; other != nil {
+				cell.CageGaps[side] = sideGap(cell, other)
+			}
+		}
+
+		if idx == slices.Min(g.board.Cages()[cage].Cells) {
+			cell.Corner = fmt.Sprintf("%d", g.board.Cages()[cage].Sum)
+		}
+	}
+}
+
+// sideGap returns the space between two cells side by side
+func sideGap(cell *Cell, other *Cell) int32 {
+	return max(other.Rect.X-(cell.Rect.X+cell.Rect.W), cell.Rect.X-(other.Rect.X+other.Rect.W),
+		other.Rect.Y-(cell.Rect.Y+cell.Rect.H), cell.Rect.Y-(other.Rect.Y+other.Rect.H))
+}
@@ -1,9 +1,6 @@
 package engine
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -23,87 +20,78 @@ type Label struct {
 	isActive  bool
 }
 
-func (l *Label) useArgs(args []interface{}) error {
-	var ok bool
-
-	pos := sdl.Point{X: l.Rect.X, Y: l.Rect.Y}
-	size := sdl.Point{X: l.Rect.W, Y: l.Rect.H}
-	bg := l.BackgroundColor
-	txt := l.Text
-	txtColor := l.TextColor
-	fntName := l.FontName
-	fntSize := l.FontSize
-
-	for i := range len(args) {
-		if i > 6 {
-			break
-		}
-
-		arg := args[i]
-		var t string
-
-		switch i {
-		case 0:
-			pos, ok = arg.(sdl.Point)
-			t = "sdl.Point"
-		case 1:
-			size, ok = arg.(sdl.Point)
-			t = "sdl.Point"
-		case 2:
-			bg, ok = arg.(sdl.Color)
-			t = "sdl.Color"
-		case 3:
-			txt, ok = arg.([]string)
-			t = "[]string"
-		case 4:
-			txtColor, ok = arg.(sdl.Color)
-			t = "sdl.Color"
-		case 5:
-			fntName, ok = arg.(string)
-			t = "string"
-		case 6:
-			fntSize, ok = arg.(int)
-			t = "int"
-		}
-
-		if !ok {
-			return fmt.Errorf("invalid argument at index %d: expected %s, got %T", i, t, arg)
-		}
+// LabelOptions configures a label. Fields left at their zero value take
+// the default noted beside them.
+type LabelOptions struct {
+	Pos  sdl.Point
+	Size sdl.Point
+
+	Color sdl.Color // the background, transparent by default
+
+	// Text holds the lines of the label, centered together
+	Text      []string
+	TextColor sdl.Color // default DEFAULT_TEXT_COLOR
+
+	FontName string // default DEFAULT_FONT
+	FontSize int    // default DEFAULT_FONT_SIZE
+}
+
+// useOptions fills in the defaults of the options and applies them
+func (l *Label) useOptions(opts LabelOptions) error {
+	if opts.TextColor == (sdl.Color{}) {
+		opts.TextColor = DEFAULT_TEXT_COLOR
 	}
 
-	l.Rect = sdl.Rect{X: pos.X, Y: pos.Y, W: size.X, H: size.Y}
-	l.BackgroundColor = bg
-	l.Text = txt
-	l.TextColor = txtColor
-	l.FontName = fntName
-	l.FontSize = fntSize
+	if opts.FontName == "" {
+		opts.FontName = DEFAULT_FONT
+	}
+
+	if opts.FontSize == 0 {
+		opts.FontSize = DEFAULT_FONT_SIZE
+	}
+
+	err := checkText("label", opts.Size, opts.FontName, opts.FontSize)
+
+	if err != nil {
+		return err
+	}
+
+	l.Rect = sdl.Rect{X: opts.Pos.X, Y: opts.Pos.Y, W: opts.Size.X, H: opts.Size.Y}
+	l.BackgroundColor = opts.Color
+	l.Text = opts.Text
+	l.TextColor = opts.TextColor
+	l.FontName = opts.FontName
+	l.FontSize = opts.FontSize
 
 	return nil
 }
 
+// Setup adds the label to a scene with the LabelOptions in args
 func (l *Label) Setup(s Scene, args []interface{}) error {
-	*l = Label{}
-
-	num_labels := 0
-
-	for _, key := range s.GetWidgetIDs() {
-		prefix := strings.Split(key, "_")[0]
+	opts, err := widgetOptions[LabelOptions]("label", args)
 
-		if prefix == "Label" {
-			num_labels++
-		}
+	if err != nil {
+		return err
 	}
 
-	id := "Label_" + fmt.Sprintf("%d", num_labels)
+	return l.SetupWith(s, opts)
+}
+
+// SetupWith adds the label to a scene, configured by opts
+func (l *Label) SetupWith(s Scene, opts LabelOptions) error {
+	*l = Label{}
 
-	l.WidgetID = id
+	l.WidgetID = nextWidgetID(s, "Label")
 
-	err := l.useArgs(args)
+	err := l.useOptions(opts)
 
 	if err != nil {
 		return err
 	}
 
+	*l.Visible() = true
+	*l.Active() = true
+
 	return s.InsertWidget(l)
 }
 
@@ -126,6 +114,10 @@ func (l *Label) Resize(size sdl.Point) {
 }
 
 func (l *Label) Draw(e *Engine) error {
+	if !l.isVisible {
+		return nil
+	}
+
 	e.Renderer.SetDrawColor(l.BackgroundColor.R, l.BackgroundColor.G, l.BackgroundColor.B, l.BackgroundColor.A)
 	e.Renderer.FillRect(&l.Rect)
 	e.Renderer.SetDrawColor(DEFAULT_DRAW.R, DEFAULT_DRAW.G, DEFAULT_DRAW.B, DEFAULT_DRAW.A)
@@ -155,3 +147,6 @@ func (l *Label) Hover(e *Engine, pos sdl.Point) {
 
 func (l *Label) Click(e *Engine, pos sdl.Point) {
 }
+
+func (l *Label) Input(e *Engine, action byte, pressed byte) {
+}
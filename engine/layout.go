@@ -0,0 +1,341 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Placeable is anything a layout can place: every widget, and the layouts
+// themselves so they can nest
+type Placeable interface {
+	SetPosition(sdl.Point)
+	Resize(sdl.Point)
+}
+
+// Layout is a container that works out where its items go from the rect it
+// is given, and does so again whenever the rect changes. Sizes, spacing and
+// padding are given in points, which scale turns into pixels.
+type Layout interface {
+	Placeable
+	Arrange(rect sdl.Rect, scale float64)
+}
+
+// Item is a widget or layout in a container along with the size in points
+// it asks for. A width or height of 0 takes all the room the container
+// gives it on that axis.
+type Item struct {
+	Placeable
+	Size sdl.Point
+}
+
+// Fixed asks for an item of the given width and height
+func Fixed(p Placeable, width int32, height int32) Item {
+	return Item{Placeable: p, Size: sdl.Point{X: width, Y: height}}
+}
+
+// Fill asks for all the room the container gives the item
+func Fill(p Placeable) Item {
+	return Item{Placeable: p}
+}
+
+// Spacer is an empty item that takes up room in a layout
+type Spacer struct{}
+
+func (Spacer) SetPosition(pos sdl.Point) {}
+
+func (Spacer) Resize(size sdl.Point) {}
+
+// Area is an empty item that keeps the rect the layout gives it, for
+// content placed by hand such as the cells of the grid
+type Area struct {
+	Rect sdl.Rect
+}
+
+func (a *Area) SetPosition(pos sdl.Point) {
+	a.Rect.X = pos.X
+	a.Rect.Y = pos.Y
+}
+
+func (a *Area) Resize(size sdl.Point) {
+	a.Rect.W = size.X
+	a.Rect.H = size.Y
+}
+
+// Insets are the room a container leaves inside each of its edges
+type Insets struct {
+	Top    int32
+	Right  int32
+	Bottom int32
+	Left   int32
+}
+
+// Pad leaves the same room inside every edge
+func Pad(all int32) Insets {
+	return Insets{Top: all, Right: all, Bottom: all, Left: all}
+}
+
+func (i Insets) inside(rect sdl.Rect, scale float64) sdl.Rect {
+	top, right, bottom, left := scaled(i.Top, scale), scaled(i.Right, scale), scaled(i.Bottom, scale), scaled(i.Left, scale)
+
+	return sdl.Rect{
+		X: rect.X + left,
+		Y: rect.Y + top,
+		W: max(0, rect.W-left-right),
+		H: max(0, rect.H-top-bottom),
+	}
+}
+
+// scaled turns a length in points into pixels
+func scaled(points int32, scale float64) int32 {
+	return int32(math.Round(float64(points) * scale))
+}
+
+// Align lines an item up within more room than it needs along one axis
+type Align int
+
+const (
+	AlignStart Align = iota
+	AlignCenter
+	AlignEnd
+)
+
+// offset returns how far into room an item of size starts
+func (a Align) offset(room int32, size int32) int32 {
+	switch a {
+	case AlignCenter:
+		return (room - size) / 2
+	case AlignEnd:
+		return room - size
+	default:
+		return 0
+	}
+}
+
+// place moves an item to rect, arranging a layout once rather than once
+// for its size and again for its position
+func place(p Placeable, rect sdl.Rect, scale float64) {
+	if layout, ok := p.(Layout); ok {
+		layout.Arrange(rect, scale)
+		return
+	}
+
+	p.Resize(sdl.Point{X: rect.W, Y: rect.H})
+	p.SetPosition(sdl.Point{X: rect.X, Y: rect.Y})
+}
+
+// fit returns where an item goes within room, filling it on each axis the
+// item asks no size for and lining it up on the others
+func fit(item Item, room sdl.Rect, scale float64, horizontal Align, vertical Align) sdl.Rect {
+	rect := room
+
+	if item.Size.X > 0 {
+		rect.W = scaled(item.Size.X, scale)
+		rect.X += horizontal.offset(room.W, rect.W)
+	}
+
+	if item.Size.Y > 0 {
+		rect.H = scaled(item.Size.Y, scale)
+		rect.Y += vertical.offset(room.H, rect.H)
+	}
+
+	return rect
+}
+
+// Stack places its items one after another down its rect, or across it
+// when Horizontal, Spacing apart. Items asking for no length share what
+// the others leave, and when none do the items are lined up by Justify.
+// Across the stack each item is lined up by Align.
+type Stack struct {
+	Rect  sdl.Rect
+	Items []Item
+
+	Horizontal bool
+	Spacing    int32
+	Padding    Insets
+	Align      Align
+	Justify    Align
+
+	scale float64
+}
+
+// VStack stacks items from top to bottom
+func VStack(spacing int32, items ...Item) *Stack {
+	return &Stack{Items: items, Spacing: spacing}
+}
+
+// HStack stacks items from left to right
+func HStack(spacing int32, items ...Item) *Stack {
+	return &Stack{Items: items, Spacing: spacing, Horizontal: true}
+}
+
+func (s *Stack) Arrange(rect sdl.Rect, scale float64) {
+	s.Rect = rect
+	s.scale = scale
+
+	if len(s.Items) == 0 {
+		return
+	}
+
+	inner := s.Padding.inside(rect, scale)
+	spacing := scaled(s.Spacing, scale)
+	room, used, fills := inner.H, spacing*int32(len(s.Items)-1), 0
+
+	if s.Horizontal {
+		room = inner.W
+	}
+
+	for _, item := range s.Items {
+		length := scaled(s.length(item.Size), scale)
+
+		if length == 0 {
+			fills++
+		}
+
+		used += length
+	}
+
+	// Filling items split the room left over, the first ones taking any
+	// pixels that do not divide evenly
+	spare := max(0, room-used)
+	pos := s.Justify.offset(spare, 0)
+
+	if fills > 0 {
+		pos = 0
+	}
+
+	filled := 0
+
+	for _, item := range s.Items {
+		length := scaled(s.length(item.Size), scale)
+
+		if length == 0 {
+			length = spare / int32(fills)
+
+			if int32(filled) < spare%int32(fills) {
+				length++
+			}
+
+			filled++
+		}
+
+		if s.Horizontal {
+			place(item.Placeable, fit(item, sdl.Rect{X: inner.X + pos, Y: inner.Y, W: length, H: inner.H}, scale, AlignStart, s.Align), scale)
+		} else {
+			place(item.Placeable, fit(item, sdl.Rect{X: inner.X, Y: inner.Y + pos, W: inner.W, H: length}, scale, s.Align, AlignStart), scale)
+		}
+
+		pos += length + spacing
+	}
+}
+
+// length is the size an item asks for along the stack
+func (s *Stack) length(size sdl.Point) int32 {
+	if s.Horizontal {
+		return size.X
+	}
+
+	return size.Y
+}
+
+func (s *Stack) SetPosition(pos sdl.Point) {
+	s.Arrange(sdl.Rect{X: pos.X, Y: pos.Y, W: s.Rect.W, H: s.Rect.H}, s.scale)
+}
+
+func (s *Stack) Resize(size sdl.Point) {
+	s.Arrange(sdl.Rect{X: s.Rect.X, Y: s.Rect.Y, W: size.X, H: size.Y}, s.scale)
+}
+
+// Grid places its items in rows of Columns equal cells, Spacing apart,
+// filling each row from the left before starting the next. The rows share
+// the height unless Square makes each as tall as the cells are wide. Items
+// asking for a size are centered in their cell.
+type Grid struct {
+	Rect  sdl.Rect
+	Items []Item
+
+	Columns int
+	Spacing sdl.Point
+	Padding Insets
+	Square  bool
+
+	scale float64
+}
+
+func (g *Grid) Arrange(rect sdl.Rect, scale float64) {
+	g.Rect = rect
+	g.scale = scale
+
+	if len(g.Items) == 0 || g.Columns < 1 {
+		return
+	}
+
+	inner := g.Padding.inside(rect, scale)
+	spacing := sdl.Point{X: scaled(g.Spacing.X, scale), Y: scaled(g.Spacing.Y, scale)}
+	columns := int32(g.Columns)
+	rows := (int32(len(g.Items)) + columns - 1) / columns
+
+	width := max(0, (inner.W-(columns-1)*spacing.X)/columns)
+	height := max(0, (inner.H-(rows-1)*spacing.Y)/rows)
+
+	if g.Square {
+		height = width
+	}
+
+	for i, item := range g.Items {
+		row, col := int32(i)/columns, int32(i)%columns
+		cell := sdl.Rect{X: inner.X + col*(width+spacing.X), Y: inner.Y + row*(height+spacing.Y), W: width, H: height}
+
+		place(item.Placeable, fit(item, cell, scale, AlignCenter, AlignCenter), scale)
+	}
+}
+
+func (g *Grid) SetPosition(pos sdl.Point) {
+	g.Arrange(sdl.Rect{X: pos.X, Y: pos.Y, W: g.Rect.W, H: g.Rect.H}, g.scale)
+}
+
+func (g *Grid) Resize(size sdl.Point) {
+	g.Arrange(sdl.Rect{X: g.Rect.X, Y: g.Rect.Y, W: size.X, H: size.Y}, g.scale)
+}
+
+// Anchor pins an item to a corner of its rect, the middle of an edge or
+// the center, as picked by Horizontal and Vertical, and moves it by Offset
+type Anchor struct {
+	Rect sdl.Rect
+	Item Item
+
+	Horizontal Align
+	Vertical   Align
+	Offset     sdl.Point
+	Padding    Insets
+
+	scale float64
+}
+
+// Center keeps an item in the middle of the rect
+func Center(item Item) *Anchor {
+	return &Anchor{Item: item, Horizontal: AlignCenter, Vertical: AlignCenter}
+}
+
+func (a *Anchor) Arrange(rect sdl.Rect, scale float64) {
+	a.Rect = rect
+	a.scale = scale
+
+	if a.Item.Placeable == nil {
+		return
+	}
+
+	spot := fit(a.Item, a.Padding.inside(rect, scale), scale, a.Horizontal, a.Vertical)
+	spot.X += scaled(a.Offset.X, scale)
+	spot.Y += scaled(a.Offset.Y, scale)
+
+	place(a.Item.Placeable, spot, scale)
+}
+
+func (a *Anchor) SetPosition(pos sdl.Point) {
+	a.Arrange(sdl.Rect{X: pos.X, Y: pos.Y, W: a.Rect.W, H: a.Rect.H}, a.scale)
+}
+
+func (a *Anchor) Resize(size sdl.Point) {
+	a.Arrange(sdl.Rect{X: a.Rect.X, Y: a.Rect.Y, W: size.X, H: size.Y}, a.scale)
+}
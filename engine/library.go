@@ -0,0 +1,614 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"main/formats"
+	"main/logic"
+	"main/storage"
+	"main/sudoku"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	LIBRARY_DIR  = "puzzles"
+	LIBRARY_ROWS = 10
+)
+
+var (
+	LIBRARY_EXTENSIONS = []string{".sdm", ".sdk", ".ss", ".pm", ".txt"}
+
+	LIBRARY_ROW_COLOR      = sdl.Color{R: 0x30, G: 0x30, B: 0x30, A: 0xFF}
+	LIBRARY_SELECTED_COLOR = sdl.Color{R: 0x40, G: 0x60, B: 0x90, A: 0xFF}
+	LIBRARY_SOLVED_COLOR   = sdl.Color{R: 0x80, G: 0xD0, B: 0x90, A: 0xFF}
+)
+
+// LibraryEntry is one puzzle from a collection file
+type LibraryEntry struct {
+	Collection string
+	Number     int
+	Puzzle     formats.Puzzle
+	Difficulty sudoku.Difficulty
+	Clues      int
+
+	// Graded is false until the puzzle's difficulty is known, which for
+	// puzzles not naming one waits on grading in the background
+	Graded bool
+
+	// path is the collection file the puzzle was read from
+	path string
+}
+
+// Name is the puzzle's own name, or its position in the collection
+func (le LibraryEntry) Name() string {
+	if le.Puzzle.Name != "" {
+		return le.Puzzle.Name
+	}
+
+	return fmt.Sprintf("%s #%d", le.Collection, le.Number)
+}
+
+// recordKey names the entry in the records, where collections hold no cages
+// or constraints
+func (le LibraryEntry) recordKey() (string, error) {
+	return recordKey(le.Puzzle.Shape, le.Puzzle.Givens, sudoku.Variant{})
+}
+
+// collection is the entries read from a file, along with the size and
+// modification time it had when read
+type collection struct {
+	size    int64
+	modTime time.Time
+	entries []LibraryEntry
+
+	// grades delivers the difficulties of entries graded in the background,
+	// and is nil once every entry is graded
+	grades chan entryGrade
+}
+
+// entryGrade is the difficulty of the entry at index idx of a collection
+type entryGrade struct {
+	idx        int
+	difficulty sudoku.Difficulty
+}
+
+type Library struct {
+	FileScene
+
+	entries  []LibraryEntry
+	filtered []int
+	records  map[string]PuzzleRecord
+
+	// collections caches the entries read from each collection file by
+	// path, so files are only parsed and graded again once they change
+	collections map[string]collection
+
+	page     int
+	selected int
+
+	// difficultyFilter is -1 to show every difficulty
+	difficultyFilter int
+	textFilter       string
+
+	rows         [LIBRARY_ROWS]*Button
+	filterButton *Button
+	searchLabel  *Label
+	pageLabel    *Label
+}
+
+func (l *Library) Setup(e *Engine, title string, args []interface{}) error {
+	*l = Library{}
+
+	err := l.setupBase(e, title)
+
+	if err != nil {
+		return err
+	}
+
+	l.records = map[string]PuzzleRecord{}
+
+	l.collections = map[string]collection{}
+
+	l.difficultyFilter = -1
+
+	return e.InsertScene(l)
+}
+
+func (l *Library) Input(e *Engine, action byte, pressed byte) {
+	if !l.isActive {
+		return
+	}
+
+	for _, widget := range l.Widgets {
+		widget.Input(e, action, pressed)
+	}
+
+	if pressed != PRESSED {
+		return
+	}
+
+	switch action {
+	case MOVE_UP:
+		l.Select(l.selected - 1)
+	case MOVE_DOWN:
+		l.Select(l.selected + 1)
+	case MOVE_LEFT:
+		l.SetPage(l.page - 1)
+	case MOVE_RIGHT:
+		l.SetPage(l.page + 1)
+	case CONFIRM:
+		l.Open(e, l.selected)
+	case BACKSPACE:
+		if runes := []rune(l.textFilter); len(runes) > 0 {
+			l.SetTextFilter(string(runes[:len(runes)-1]))
+		}
+	case CANCEL:
+		// Escape clears the search first, then leaves the library
+		if l.textFilter != "" {
+			l.SetTextFilter("")
+		} else if err := e.Switch("Main Menu"); err != nil {
+			log.Printf("Error leaving library: %s\n", err)
+		}
+	}
+}
+
+// TextInput types into the search filter
+func (l *Library) TextInput(e *Engine, text string) {
+	if l.isActive {
+		l.SetTextFilter(l.textFilter + text)
+	}
+}
+
+func (l *Library) RenderWidgets(e *Engine) error {
+	l.poll()
+
+	return l.FileScene.RenderWidgets(e)
+}
+
+// Enter rescans the collections so new and changed files and records show up
+func (l *Library) Enter(e *Engine) {
+	records, err := LoadRecords()
+
+	if err != nil {
+		log.Printf("Error loading puzzle records: %s\n", err)
+	}
+
+	l.records = records
+	l.Load()
+}
+
+// LibraryDirs lists the directories searched for collection files: the
+// puzzles folder in the data directory, then one beside the working directory
+func LibraryDirs() []string {
+	dirs := []string{}
+
+	if dir, err := storage.Path(LIBRARY_DIR); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	return append(dirs, LIBRARY_DIR)
+}
+
+// Load lists the entries of every collection file, reading only files not
+// seen before or changed since, and skipping files that fail to parse
+func (l *Library) Load() {
+	l.entries = []LibraryEntry{}
+	collections := map[string]collection{}
+	seen := map[string]bool{}
+
+	for _, dir := range LibraryDirs() {
+		abs, err := filepath.Abs(dir)
+
+		if err != nil || seen[abs] {
+			continue
+		}
+
+		seen[abs] = true
+
+		files, err := os.ReadDir(dir)
+
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			ext := strings.ToLower(filepath.Ext(file.Name()))
+
+			if file.IsDir() || !slices.Contains(LIBRARY_EXTENSIONS, ext) {
+				continue
+			}
+
+			info, err := file.Info()
+
+			if err != nil {
+				log.Printf("Error reading collection: %s\n", err)
+				continue
+			}
+
+			path := filepath.Join(abs, file.Name())
+			cached, ok := l.collections[path]
+
+			if !ok || cached.size != info.Size() || !cached.modTime.Equal(info.ModTime()) {
+				cached, err = readCollection(path, info)
+
+				if err != nil {
+					log.Printf("Error reading collection: %s\n", err)
+					continue
+				}
+			}
+
+			collections[path] = cached
+			l.entries = append(l.entries, cached.entries...)
+		}
+	}
+
+	// Files that were removed drop out of the cache
+	l.collections = collections
+	l.applyFilter()
+}
+
+// readCollection parses a collection file, trusting any difficulty named in
+// the file and grading the other puzzles in the background, so a large
+// collection does not hold up the library
+func readCollection(path string, info os.FileInfo) (collection, error) {
+	puzzles, err := formats.ReadFile(path)
+
+	if err != nil {
+		return collection{}, err
+	}
+
+	read := collection{size: info.Size(), modTime: info.ModTime()}
+	name := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
+	ungraded := []int{}
+
+	for i, puzzle := range puzzles {
+		entry := LibraryEntry{
+			Collection: name,
+			Number:     i + 1,
+			Puzzle:     puzzle,
+			Clues:      puzzle.Givens.Clues(),
+			path:       path,
+		}
+
+		entry.Difficulty, err = sudoku.ParseDifficulty(puzzle.Difficulty)
+		entry.Graded = err == nil
+
+		if !entry.Graded {
+			ungraded = append(ungraded, i)
+		}
+
+		read.entries = append(read.entries, entry)
+	}
+
+	if len(ungraded) == 0 {
+		return read, nil
+	}
+
+	// The channel holds every grade, so grading never waits on the library
+	grades := make(chan entryGrade, len(ungraded))
+	read.grades = grades
+
+	go func() {
+		for _, idx := range ungraded {
+			grades <- entryGrade{idx: idx, difficulty: logic.Grade(puzzles[idx].Shape, puzzles[idx].Givens)}
+		}
+
+		close(grades)
+	}()
+
+	return read, nil
+}
+
+// poll fills in the grades finished in the background since the last frame
+func (l *Library) poll() {
+	graded := false
+
+	for path, read := range l.collections {
+		if read.grades == nil {
+			continue
+		}
+
+	drain:
+		for {
+			select {
+			case result, ok := <-read.grades:
+				if !ok {
+					read.grades = nil
+					l.collections[path] = read
+					break drain
+				}
+
+				read.entries[result.idx].Difficulty = result.difficulty
+				read.entries[result.idx].Graded = true
+				l.setGrade(read.entries[result.idx])
+				graded = true
+			default:
+				break drain
+			}
+		}
+	}
+
+	if graded {
+		l.applyFilter()
+	}
+}
+
+// setGrade copies the grade of a collection entry to the listed entries
+func (l *Library) setGrade(graded LibraryEntry) {
+	for i, entry := range l.entries {
+		if entry.path == graded.path && entry.Number == graded.Number {
+			l.entries[i].Difficulty = graded.Difficulty
+			l.entries[i].Graded = true
+			return
+		}
+	}
+}
+
+func (l *Library) matches(entry LibraryEntry) bool {
+	if l.difficultyFilter >= 0 && (!entry.Graded || entry.Difficulty != sudoku.Difficulty(l.difficultyFilter)) {
+		return false
+	}
+
+	search := strings.ToLower(l.textFilter)
+
+	for _, field := range []string{entry.Name(), entry.Collection, entry.Puzzle.Author} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Library) applyFilter() {
+	l.filtered = []int{}
+
+	for i, entry := range l.entries {
+		if l.matches(entry) {
+			l.filtered = append(l.filtered, i)
+		}
+	}
+
+	l.selected = min(l.selected, len(l.filtered)-1)
+	l.SetPage(l.page)
+}
+
+func (l *Library) pages() int {
+	return max(1, (len(l.filtered)+LIBRARY_ROWS-1)/LIBRARY_ROWS)
+}
+
+// SetPage shows a page of the filtered puzzles, clamped to the pages there are
+func (l *Library) SetPage(page int) {
+	l.page = max(0, min(page, l.pages()-1))
+
+	if l.selected/LIBRARY_ROWS != l.page {
+		l.selected = -1
+	}
+
+	l.updateRows()
+}
+
+// Select highlights a filtered puzzle, turning the page to show it
+func (l *Library) Select(idx int) {
+	if len(l.filtered) == 0 {
+		return
+	}
+
+	l.selected = max(0, min(idx, len(l.filtered)-1))
+	l.page = l.selected / LIBRARY_ROWS
+	l.updateRows()
+}
+
+func (l *Library) SetTextFilter(text string) {
+	l.textFilter = text
+	l.applyFilter()
+}
+
+func (l *Library) SetDifficultyFilter(filter int) {
+	l.difficultyFilter = filter
+
+	if l.filterButton != nil {
+		l.filterButton.Text = "All"
+
+		if filter >= 0 {
+			l.filterButton.Text = sudoku.Difficulty(filter).String()
+		}
+	}
+
+	l.applyFilter()
+}
+
+func (l *Library) updateRows() {
+	for i, row := range l.rows {
+		if row == nil {
+			continue
+		}
+
+		idx := l.page*LIBRARY_ROWS + i
+
+		if idx >= len(l.filtered) {
+			*row.Visible() = false
+			continue
+		}
+
+		entry := l.entries[l.filtered[idx]]
+		// An entry whose key cannot be made shows as never played
+		key, _ := entry.recordKey()
+		record := l.records[key]
+
+		best := "--:--"
+		status := "New"
+
+		if record.BestMS > 0 {
+			best = formatDuration(time.Duration(record.BestMS) * time.Millisecond)
+		}
+
+		if record.Solved {
+			status = "Solved"
+		} else if record.Started {
+			status = "Started"
+		}
+
+		name := []rune(entry.Name())
+
+		if len(name) > 28 {
+			name = append(name[:27], '~')
+		}
+
+		difficulty := "?"
+
+		if entry.Graded {
+			difficulty = entry.Difficulty.String()
+		}
+
+		*row.Visible() = true
+		row.Text = fmt.Sprintf("%-28s %-8s %2d clues %8s  %-7s", string(name), difficulty, entry.Clues, best, status)
+		row.TextColor = sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+		row.InitBackgroundColor = LIBRARY_ROW_COLOR
+
+		if record.Solved {
+			row.TextColor = LIBRARY_SOLVED_COLOR
+		}
+
+		if idx == l.selected {
+			row.InitBackgroundColor = LIBRARY_SELECTED_COLOR
+		}
+
+		row.BackgroundColor = row.InitBackgroundColor
+	}
+
+	if l.searchLabel != nil {
+		l.searchLabel.Text = []string{"Search: " + l.textFilter + "_"}
+	}
+
+	if l.pageLabel != nil {
+		l.pageLabel.Text = []string{
+			fmt.Sprintf("Page %d / %d", l.page+1, l.pages()),
+			fmt.Sprintf("%d puzzles", len(l.filtered)),
+		}
+	}
+}
+
+// Open starts a filtered puzzle in the game scene
+func (l *Library) Open(e *Engine, idx int) {
+	if idx < 0 || idx >= len(l.filtered) {
+		return
+	}
+
+	entry := l.entries[l.filtered[idx]]
+
+	game, ok := e.Scenes["Game"].(*Game)
+
+	if !ok {
+		log.Printf("Error opening puzzle: no game scene\n")
+		return
+	}
+
+	err := game.OpenPuzzle(entry.Puzzle)
+
+	if err != nil {
+		log.Printf("Error opening %s: %s\n", entry.Name(), err)
+		return
+	}
+
+	key, err := entry.recordKey()
+
+	if err == nil {
+		err = recordStart(key)
+	}
+
+	if err != nil {
+		log.Printf("Error recording puzzle start: %s\n", err)
+	}
+
+	err = e.Switch("Game")
+
+	if err != nil {
+		log.Printf("Error opening puzzle: %s\n", err)
+	}
+}
+
+// NewLibrary builds the library from its scene file, filling the rows node
+// with a button for each row of a page
+func (l *Library) NewLibrary(e *Engine, file SceneFile) error {
+	l.Actions = map[string]SceneAction{
+		"filter": simpleAction(func(e *Engine) {
+			next := l.difficultyFilter + 1
+
+			if next >= len(sudoku.Difficulties()) {
+				next = -1
+			}
+
+			l.SetDifficultyFilter(next)
+		}),
+		"prev": simpleAction(func(e *Engine) {
+			l.SetPage(l.page - 1)
+		}),
+		"next": simpleAction(func(e *Engine) {
+			l.SetPage(l.page + 1)
+		}),
+	}
+
+	err := l.NewFileScene(e, file)
+
+	if err != nil {
+		return err
+	}
+
+	l.filterButton, err = named[*Button](&l.FileScene, "filter")
+
+	if err != nil {
+		return err
+	}
+
+	l.searchLabel, err = named[*Label](&l.FileScene, "search")
+
+	if err != nil {
+		return err
+	}
+
+	l.pageLabel, err = named[*Label](&l.FileScene, "page")
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := named[*Stack](&l.FileScene, "rows")
+
+	if err != nil {
+		return err
+	}
+
+	for i := range LIBRARY_ROWS {
+		row := &Button{}
+
+		err = row.SetupWith(l, ButtonOptions{
+			Color:    LIBRARY_ROW_COLOR,
+			FontName: "lotuscoder_normal",
+			FontSize: 14,
+			Brighten: true,
+			OnClick: func(e *Engine) {
+				l.Open(e, l.page*LIBRARY_ROWS+i)
+			},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		l.rows[i] = row
+		rows.Items = append(rows.Items, Fixed(row, 0, 36))
+	}
+
+	l.Arrange(e)
+	l.updateRows()
+
+	return nil
+}
@@ -1,196 +1,426 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"slices"
+	"time"
 
-	"github.com/veandco/go-sdl2/sdl"
+	"main/generator"
+	"main/sudoku"
+)
+
+// START_ATTEMPTS is how many seeds the menu tries for each difficulty
+const START_ATTEMPTS = 4
+
+// LEVEL_TIMEOUT bounds the search for a puzzle of each difficulty before an
+// easier one is tried, and GENERATE_TIMEOUT the whole search before the
+// menu reports that no puzzle was found
+const (
+	LEVEL_TIMEOUT    = 10 * time.Second
+	GENERATE_TIMEOUT = 30 * time.Second
+)
+
+const (
+	// MAX_JIGSAW_SIZE is the largest grid the menu offers jigsaw regions on
+	MAX_JIGSAW_SIZE = 9
+	// MAX_LINE_SIZE is the largest grid the menu offers line variants on
+	MAX_LINE_SIZE = 9
+	// MAX_EDGE_SIZE is the largest grid the menu offers edge markers on
+	MAX_EDGE_SIZE = 9
 )
 
 type Menu struct {
-	Title string
+	FileScene
+
+	Difficulty sudoku.Difficulty
+
+	// Variant is the kind of puzzle the start button generates
+	Variant MenuVariant
+
+	// Shape is the size of grid the start button generates
+	Shape sudoku.Shape
 
-	Widgets map[string]Widget
+	continueButton   *Button
+	startButton      *Button
+	startText        string
+	difficultyButton *Button
+	variantButton    *Button
+	sizeButton       *Button
+	statusLabel      *Label
 
-	isActive bool
+	// generating delivers the puzzle being generated in the background, and
+	// is nil when none is
+	generating chan generated
 }
 
 func (m *Menu) Setup(e *Engine, title string, args []interface{}) error {
 	*m = Menu{}
 
-	_, ok := e.Scenes[title]
+	err := m.setupBase(e, title)
 
-	if ok {
-		return fmt.Errorf("scene with same title already exists: %s", title)
+	if err != nil {
+		return err
 	}
 
-	m.Title = title
+	m.Shape = sudoku.Classic
+	m.Variant = MenuVariants()[0]
 
-	m.Widgets = map[string]Widget{}
+	return e.InsertScene(m)
+}
 
-	m.isActive = false
+func (m *Menu) RenderWidgets(e *Engine) error {
+	m.poll(e)
 
-	return e.InsertScene(m)
+	return m.FileScene.RenderWidgets(e)
 }
 
-func (m *Menu) Delete(e *Engine) error {
-	return e.DeleteScene(m.Title)
+func (m *Menu) Input(e *Engine, action byte, pressed byte) {
+	if m.isActive {
+		for _, widget := range m.Widgets {
+			widget.Input(e, action, pressed)
+		}
+
+		if action == PASTE && pressed == PRESSED {
+			if err := e.PastePuzzle(); err != nil {
+				log.Printf("Error pasting puzzle: %s\n", err)
+			}
+		}
+	}
+}
+
+// Enter shows the continue button only when there is a saved game to resume
+func (m *Menu) Enter(e *Engine) {
+	if m.continueButton != nil {
+		*m.continueButton.Visible() = HasSavedGame()
+	}
 }
 
-func (m *Menu) GetTitle() string {
-	return m.Title
+// MenuVariant is a kind of puzzle the menu offers: classic, Killer, Jigsaw
+// or one with a constraint, kind of line or kind of edge marker
+type MenuVariant struct {
+	Name        string
+	Killer      bool
+	Jigsaw      bool
+	Constraints sudoku.Constraints
+	Lines       sudoku.LineConstraint
+	Edges       sudoku.EdgeConstraint
 }
 
-func (m *Menu) InsertWidget(widget Widget) error {
-	_, ok := m.Widgets[widget.GetWidgetID()]
+// MenuVariants lists the variants in the order the variant selector cycles
+// through them, with each constraint, kind of line and kind of edge marker
+// offered on its own. Edge markers that can be negative are offered both
+// ways.
+func MenuVariants() []MenuVariant {
+	variants := []MenuVariant{{Name: "Classic"}, {Name: "Killer", Killer: true}, {Name: "Jigsaw", Jigsaw: true}}
 
-	if ok {
-		return fmt.Errorf("widget already exists: %s", widget.GetWidgetID())
+	for _, constraint := range sudoku.AllConstraints() {
+		variants = append(variants, MenuVariant{Name: constraint.Name(), Constraints: sudoku.Constraints{constraint}})
 	}
 
-	m.Widgets[widget.GetWidgetID()] = widget
+	for _, kind := range sudoku.AllLineConstraints() {
+		variants = append(variants, MenuVariant{Name: kind.Name(), Lines: kind})
+	}
 
-	return nil
+	for _, kind := range sudoku.AllEdgeConstraints() {
+		variants = append(variants, MenuVariant{Name: kind.Name(), Edges: kind})
+
+		if kind.Negatable() {
+			variants = append(variants, MenuVariant{Name: "Negative " + kind.Name(), Edges: kind.WithEdges(nil, true)})
+		}
+	}
+
+	return variants
 }
 
-func (m *Menu) RenderWidgets(e *Engine) error {
-	for _, widget := range m.Widgets {
-		err := widget.Draw(e)
+// Fits reports whether puzzles of the variant can be generated on a shape
+func (v MenuVariant) Fits(shape sudoku.Shape) bool {
+	// Layouts of grids are only offered with classic rules
+	if shape.Multi() && (v.Killer || v.Jigsaw || v.Lines != nil || v.Edges != nil) {
+		return false
+	}
 
-		if err != nil {
-			return err
-		}
+	if v.Killer && shape.Size() > sudoku.MaxCageDigits {
+		return false
 	}
 
-	return nil
+	if v.Jigsaw && shape.Size() > MAX_JIGSAW_SIZE {
+		return false
+	}
+
+	if v.Lines != nil && shape.Size() > MAX_LINE_SIZE {
+		return false
+	}
+
+	if v.Edges != nil && shape.Size() > MAX_EDGE_SIZE {
+		return false
+	}
+
+	return v.Constraints.Validate(shape) == nil
 }
 
-func (m *Menu) ContainsWidget(widgetID string) bool {
-	_, ok := m.Widgets[widgetID]
+func (v MenuVariant) options(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64) generator.Options {
+	switch {
+	case v.Killer:
+		return generator.KillerOptions(shape, difficulty, seed)
+	case v.Jigsaw:
+		return generator.JigsawOptions(shape, difficulty, seed)
+	case v.Lines != nil:
+		return generator.LineOptions(shape, difficulty, seed, v.Lines)
+	case v.Edges != nil:
+		return generator.EdgeOptions(shape, difficulty, seed, v.Edges)
+	case len(v.Constraints) > 0:
+		return generator.VariantOptions(shape, difficulty, seed, v.Constraints)
+	default:
+		return generator.DefaultOptions(shape, difficulty, seed)
+	}
+}
 
-	return ok
+// generated is what a background generation hands back to the menu
+type generated struct {
+	puzzle    generator.Puzzle
+	requested sudoku.Difficulty
+	err       error
 }
 
-func (m *Menu) DeleteWidget(widgetID string) error {
-	if m.ContainsWidget(widgetID) {
-		delete(m.Widgets, widgetID)
-	} else {
-		return fmt.Errorf("no widget exists with ID: %s", widgetID)
+// StartPuzzle generates a puzzle of the chosen size, difficulty and variant
+// in the background, leaving the window responsive until poll picks it up
+func (m *Menu) StartPuzzle(seed int64) {
+	if m.generating != nil {
+		return
 	}
 
-	// Defragment widgets by compressing ID values
-	seen_widget_types := map[string]int{}
-	widget_buckets := map[string][]Widget{}
+	result := make(chan generated, 1)
+	variant, shape, requested := m.Variant, m.Shape, m.Difficulty
 
-	for id, widget := range m.Widgets {
-		base_name := strings.Split(id, "_")[0]
+	m.generating = result
+	m.startButton.Text = "Generating..."
+	m.statusLabel.Text = []string{fmt.Sprintf("Generating a %s puzzle", requested)}
 
-		seen_widget_types[base_name]++
-		widget_buckets[base_name] = append(widget_buckets[base_name], widget)
-	}
+	go func() {
+		result <- generatePuzzle(variant, shape, requested, seed)
+	}()
+}
+
+// generatePuzzle looks for a puzzle at the requested difficulty. Variants
+// and small grids do not reach every grade for every seed, so a few of the
+// following seeds are tried, within LEVEL_TIMEOUT, before settling for an
+// easier grade, until GENERATE_TIMEOUT runs out.
+func generatePuzzle(variant MenuVariant, shape sudoku.Shape, requested sudoku.Difficulty, seed int64) generated {
+	limit := time.Now().Add(GENERATE_TIMEOUT)
+	err := errors.New("no difficulty to try")
 
-	for base_name, widget_bucket := range widget_buckets {
-		for i := range seen_widget_types[base_name] {
-			*widget_bucket[i].ID() = base_name + fmt.Sprintf("_%d", i)
+	for difficulty := requested; difficulty >= sudoku.Easy && time.Now().Before(limit); difficulty-- {
+		deadline := time.Now().Add(LEVEL_TIMEOUT)
+
+		if deadline.After(limit) {
+			deadline = limit
+		}
+
+		for attempt := range START_ATTEMPTS {
+			opts := variant.options(shape, difficulty, seed+int64(attempt))
+			opts.Deadline = deadline
+
+			var puzzle generator.Puzzle
+
+			puzzle, err = generator.Generate(opts)
+
+			if err == nil {
+				return generated{puzzle: puzzle, requested: requested}
+			}
+
+			if errors.Is(err, generator.ErrDeadline) {
+				break
+			}
 		}
 	}
 
-	return nil
+	return generated{requested: requested, err: err}
 }
 
-func (m *Menu) GetWidgetIDs() []string {
-	widgetIDs := []string{}
+// poll starts the puzzle generated in the background once it is ready,
+// telling the player if it is easier than they asked for, or reports that
+// none was found
+func (m *Menu) poll(e *Engine) {
+	if m.generating == nil {
+		return
+	}
+
+	var result generated
 
-	for id := range m.Widgets {
-		widgetIDs = append(widgetIDs, id)
+	select {
+	case result = <-m.generating:
+	default:
+		return
 	}
 
-	return widgetIDs
-}
+	m.generating = nil
+	m.startButton.Text = m.startText
+	m.statusLabel.Text = []string{""}
+
+	if result.err != nil {
+		log.Printf("Error generating puzzle: %s\n", result.err)
+		m.statusLabel.Text = []string{fmt.Sprintf("No %s puzzle could be found, try another difficulty", result.requested)}
+		return
+	}
+
+	game, ok := e.Scenes["Game"].(*Game)
+
+	if !ok {
+		log.Fatalf("Error starting puzzle: no game scene\n")
+	}
+
+	err := game.Play(result.puzzle)
+
+	if err != nil {
+		log.Printf("Error starting puzzle: %s\n", err)
+		m.statusLabel.Text = []string{"The puzzle could not be started"}
+		return
+	}
+
+	if result.puzzle.Difficulty != result.requested {
+		err = game.setHintText(e, fmt.Sprintf("No %s puzzle was found in time, so this one is %s", result.requested, result.puzzle.Difficulty))
+
+		if err != nil {
+			log.Printf("Error showing difficulty: %s\n", err)
+		}
+	}
 
-func (m *Menu) Hover(e *Engine, pos sdl.Point) {
 	if m.isActive {
-		for _, widget := range m.Widgets {
-			widget.Hover(e, pos)
+		err = e.Switch("Game")
+
+		if err != nil {
+			log.Printf("Error starting puzzle: %s\n", err)
 		}
 	}
 }
 
-func (m *Menu) Click(e *Engine, pos sdl.Point) {
-	if m.isActive {
-		for _, widget := range m.Widgets {
-			widget.Click(e, pos)
+// NextShape picks the next size of grid or layout of grids, skipping those
+// the variant does not fit
+func (m *Menu) NextShape() {
+	shapes := append(sudoku.Shapes(), sudoku.MultiShapes()...)
+	current := slices.Index(shapes, m.Shape)
+
+	for i := 1; i <= len(shapes); i++ {
+		shape := shapes[(current+i)%len(shapes)]
+
+		if m.Variant.Fits(shape) {
+			m.Shape = shape
+			return
 		}
 	}
 }
 
-func (m *Menu) Active() *bool {
-	return &m.isActive
+// NextVariant picks the next variant, going back to the classic grid size
+// if the variant does not fit the current one
+func (m *Menu) NextVariant() {
+	variants := MenuVariants()
+	current := slices.IndexFunc(variants, func(v MenuVariant) bool { return v.Name == m.Variant.Name })
+	m.Variant = variants[(current+1)%len(variants)]
+
+	if !m.Variant.Fits(m.Shape) {
+		m.Shape = sudoku.Classic
+	}
 }
 
-func (m *Menu) MainMenu(e *Engine) error {
-	windWidth, _ := e.Window.GetSize()
+// MainMenu builds the menu from its scene file, giving its buttons the
+// actions that start and resume games and pick what to generate
+func (m *Menu) MainMenu(e *Engine, file SceneFile) error {
+	m.Actions = map[string]SceneAction{
+		"start": simpleAction(func(e *Engine) {
+			m.StartPuzzle(time.Now().UnixNano())
+		}),
+
+		"continue": {Run: func(e *Engine, arg string) error {
+			game, ok := e.Scenes["Game"].(*Game)
 
-	titleFont := "lotuscoder_bold"
-	titleSize := 36
+			if !ok {
+				return fmt.Errorf("no game scene")
+			}
 
-	buttonFont := "lotuscoder_normal"
-	buttonFontSize := 24
+			err := game.Resume()
 
-	// Add title to menu scene
-	titleLabel := &Label{}
-	titleText := []string{"Vy's Sudoku"}
+			if err != nil {
+				*m.continueButton.Visible() = HasSavedGame()
+
+				return fmt.Errorf("resuming saved game: %s", err)
+			}
+
+			return e.Switch("Game")
+		}},
+
+		"difficulty": simpleAction(func(e *Engine) {
+			difficulties := sudoku.Difficulties()
+			m.Difficulty = difficulties[(int(m.Difficulty)+1)%len(difficulties)]
+			m.showChoices()
+		}),
+
+		"variant": simpleAction(func(e *Engine) {
+			m.NextVariant()
+			m.showChoices()
+		}),
+
+		"size": simpleAction(func(e *Engine) {
+			m.NextShape()
+			m.showChoices()
+		}),
+	}
 
-	err := titleLabel.Setup(m, []interface{}{
-		sdl.Point{X: 0, Y: 0},
-		sdl.Point{X: windWidth, Y: 40},
-		sdl.Color{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
-		titleText,
-		sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
-		titleFont, titleSize,
-	})
+	err := m.NewFileScene(e, file)
 
 	if err != nil {
 		return err
 	}
 
-	// Add button to menu scene
-	startButton := &Button{}
-	startText := "Start Game"
-
-	err = startButton.Setup(m, []interface{}{
-		sdl.Point{X: 325, Y: 284},
-		sdl.Point{X: 150, Y: 32},
-		sdl.Color{R: 0xAF, G: 0xAF, B: 0xAF, A: 0xFF},
-		startText,
-		sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
-		buttonFont, buttonFontSize,
-		func(e *Engine) {
-			startButton.BackgroundColor = sdl.Color{
-				R: uint8(min(uint16(0xCF), uint16(startButton.BackgroundColor.R)+uint16(0x66))),
-				G: uint8(min(uint16(0xCF), uint16(startButton.BackgroundColor.G)+uint16(0x66))),
-				B: uint8(min(uint16(0xCF), uint16(startButton.BackgroundColor.B)+uint16(0x66))),
-				A: startButton.BackgroundColor.A,
-			}
-		},
-		func(e *Engine) {
-			startButton.BackgroundColor = startButton.InitBackgroundColor
-		},
-		func(e *Engine) {
-			err := e.Switch("Game")
+	m.continueButton, err = named[*Button](&m.FileScene, "continue")
 
-			if err != nil {
-				log.Fatalf("Error during click for widget %s: %s\n", startButton.GetWidgetID(), err)
-			}
+	if err != nil {
+		return err
+	}
+
+	m.startButton, err = named[*Button](&m.FileScene, "start")
+
+	if err != nil {
+		return err
+	}
+
+	// The start button reads "Generating..." while a puzzle is generated
+	m.startText = m.startButton.Text
+
+	m.difficultyButton, err = named[*Button](&m.FileScene, "difficulty")
+
+	if err != nil {
+		return err
+	}
+
+	m.variantButton, err = named[*Button](&m.FileScene, "variant")
+
+	if err != nil {
+		return err
+	}
 
-			startButton.BackgroundColor = startButton.InitBackgroundColor
-		},
-	})
+	m.sizeButton, err = named[*Button](&m.FileScene, "size")
 
 	if err != nil {
 		return err
 	}
 
+	// Progress and failures of generating a puzzle are shown in the status
+	m.statusLabel, err = named[*Label](&m.FileScene, "status")
+
+	if err != nil {
+		return err
+	}
+
+	m.showChoices()
+
 	return nil
 }
+
+// showChoices writes the difficulty, variant and size to their buttons
+func (m *Menu) showChoices() {
+	m.difficultyButton.Text = m.Difficulty.String()
+	m.variantButton.Text = m.Variant.Name
+	m.sizeButton.Text = m.Shape.String()
+}
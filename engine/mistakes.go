@@ -0,0 +1,53 @@
+package engine
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// Mistake modes decide which errors are shown while playing
+const (
+	MISTAKES_OFF = iota
+	MISTAKES_CONFLICTS
+	MISTAKES_SOLUTION
+	MISTAKES_ON_DEMAND
+)
+
+var (
+	MISTAKE_MODE_NAMES = []string{"No Check", "Rules", "Solution", "Manual"}
+
+	MISTAKE_CELL_COLOR = sdl.Color{R: 0xF0, G: 0xB8, B: 0xB8, A: 0xFF}
+	MISTAKE_TEXT_COLOR = sdl.Color{R: 0xC0, G: 0x10, B: 0x10, A: 0xFF}
+)
+
+func (g *Game) SetMistakeMode(mode int) {
+	g.Settings.MistakeMode = mode
+	g.mistakeButton.Text = MISTAKE_MODE_NAMES[mode]
+	g.checking = false
+	g.updateCells()
+}
+
+// CheckBoard marks every wrong entry until the board next changes
+func (g *Game) CheckBoard(e *Engine) {
+	g.checking = true
+	g.updateCells()
+}
+
+// mistakes marks the cells to show as errors under the current mode
+func (g *Game) mistakes() []bool {
+	mistakes := make([]bool, g.board.Shape().NumCells())
+
+	switch g.Settings.MistakeMode {
+	case MISTAKES_CONFLICTS:
+		mistakes = g.board.Conflicts()
+	case MISTAKES_SOLUTION:
+		mistakes = g.board.Mistakes(g.solution)
+	}
+
+	if g.checking {
+		wrong := g.board.Mistakes(g.solution)
+
+		for idx := range mistakes {
+			mistakes[idx] = mistakes[idx] || wrong[idx]
+		}
+	}
+
+	return mistakes
+}
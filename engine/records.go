@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"main/storage"
+	"main/sudoku"
+)
+
+const RECORDS_FILE = "records.json"
+
+// PuzzleRecord tracks how a player has done on one puzzle, keyed by
+// recordKey
+type PuzzleRecord struct {
+	Started bool  `json:"started"`
+	Solved  bool  `json:"solved"`
+	BestMS  int64 `json:"best_ms,omitempty"`
+}
+
+// LoadRecords reads the puzzle records, treating a missing file as no records
+func LoadRecords() (map[string]PuzzleRecord, error) {
+	records := map[string]PuzzleRecord{}
+
+	err := storage.ReadJSON(RECORDS_FILE, &records)
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return map[string]PuzzleRecord{}, err
+	}
+
+	return records, nil
+}
+
+// recordKey names a puzzle in the records by its givens along with the
+// same shape, cages and constraints a save holds, since a variant of the
+// same givens is another puzzle. A classic grid is named by its givens
+// alone, as every puzzle was before variants.
+func recordKey(shape sudoku.Shape, givens sudoku.Grid, variant sudoku.Variant) (string, error) {
+	if !shape.Jigsaw() && !shape.Multi() && len(variant.Cages) == 0 && len(variant.Constraints) == 0 {
+		return givens.String(), nil
+	}
+
+	data, err := json.Marshal(struct {
+		Shape       sudoku.Shape            `json:"shape"`
+		Cages       []sudoku.Cage           `json:"cages,omitempty"`
+		Constraints []sudoku.ConstraintSpec `json:"constraints,omitempty"`
+	}{shape, variant.Cages, variant.Constraints.Specs()})
+
+	if err != nil {
+		return "", err
+	}
+
+	return givens.String() + " " + string(data), nil
+}
+
+func updateRecord(key string, update func(record *PuzzleRecord)) error {
+	records, err := LoadRecords()
+
+	if err != nil {
+		return err
+	}
+
+	record := records[key]
+	update(&record)
+	records[key] = record
+
+	return storage.WriteJSON(RECORDS_FILE, records)
+}
+
+func recordStart(key string) error {
+	return updateRecord(key, func(record *PuzzleRecord) {
+		record.Started = true
+	})
+}
+
+func recordSolve(key string, elapsed time.Duration) error {
+	return updateRecord(key, func(record *PuzzleRecord) {
+		record.Started = true
+		record.Solved = true
+
+		if ms := elapsed.Milliseconds(); ms > 0 && (record.BestMS == 0 || ms < record.BestMS) {
+			record.BestMS = ms
+		}
+	})
+}
+
+// formatDuration shows a time as m:ss, or h:mm:ss past an hour
+func formatDuration(d time.Duration) string {
+	secs := int64(d / time.Second)
+
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
@@ -0,0 +1,37 @@
+package engine
+
+import "github.com/veandco/go-sdl2/sdl"
+
+var REGION_COLOR = sdl.Color{R: 0x30, G: 0x30, B: 0x40, A: 0xFF}
+
+// updateRegions marks the sides of each cell on the border of its jigsaw
+// region, including the outside of the grid. Regular grids leave the edges
+// clear since their boxes are spaced apart instead.
+func (g *Game) updateRegions() {
+	shape := g.board.Shape()
+
+	for idx, cell := range g.cells {
+		if cell == nil {
+			continue
+		}
+
+		cell.RegionEdges = [4]bool{}
+
+		if !shape.Jigsaw() {
+			continue
+		}
+
+		row, col := shape.RowCol(idx)
+
+		for side, d := range [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} {
+			r, c := row+d[0], col+d[1]
+
+			if r < 0 || r >= shape.Size() || c < 0 || c >= shape.Size() {
+				cell.RegionEdges[side] = true
+				continue
+			}
+
+			cell.RegionEdges[side] = shape.BoxOf(shape.Index(r, c)) != shape.BoxOf(idx)
+		}
+	}
+}
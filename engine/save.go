@@ -0,0 +1,273 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"main/storage"
+	"main/sudoku"
+)
+
+const (
+	SAVE_FILE    = "save.json"
+	SAVE_VERSION = 2
+)
+
+// SavedGame is the on-disk form of a game in progress. Version is bumped
+// whenever the layout changes so older files can be migrated on load.
+type SavedGame struct {
+	Version int `json:"version"`
+
+	// Shape was added in version 2, before which every grid was classic
+	Shape   sudoku.Shape        `json:"shape"`
+	Givens  string              `json:"givens"`
+	Entries string              `json:"entries"`
+	Notes   []sudoku.Candidates `json:"notes"`
+	Colors  []byte              `json:"colors"`
+	Cages   []sudoku.Cage       `json:"cages,omitempty"`
+
+	// Constraints holds the variant rules of the puzzle, if any, along with
+	// the cells of any lines and the markers of any edges
+	Constraints []sudoku.ConstraintSpec `json:"constraints,omitempty"`
+
+	Seed       int64  `json:"seed"`
+	Difficulty string `json:"difficulty"`
+	Hints      int    `json:"hints"`
+	ElapsedMS  int64  `json:"elapsed_ms"`
+
+	Undo []sudoku.Command `json:"undo"`
+	Redo []sudoku.Command `json:"redo"`
+
+	Settings Settings `json:"settings"`
+}
+
+func (g *Game) Snapshot() SavedGame {
+	shape := g.board.Shape()
+	entries := sudoku.NewGrid(shape)
+	saved := SavedGame{
+		Version:    SAVE_VERSION,
+		Shape:      shape,
+		Givens:     g.board.Givens().String(),
+		Notes:      make([]sudoku.Candidates, shape.NumCells()),
+		Colors:     make([]byte, shape.NumCells()),
+		Cages:      g.board.Cages(),
+		Seed:       g.seed,
+		Difficulty: g.difficulty.String(),
+		Hints:      g.hints,
+		ElapsedMS:  g.elapsed.Milliseconds(),
+		Settings:   g.Settings,
+
+		Constraints: g.board.Constraints().Specs(),
+	}
+
+	for idx := range entries {
+		if !g.board.Given(idx) {
+			entries[idx] = g.board.Value(idx)
+		}
+
+		saved.Notes[idx] = g.board.Notes(idx)
+		saved.Colors[idx] = g.board.Color(idx)
+	}
+
+	saved.Entries = entries.String()
+	saved.Undo, saved.Redo = g.history.Commands()
+
+	return saved
+}
+
+// checkedSave is what a save holds once read back and checked, ready to be
+// restored
+type checkedSave struct {
+	shape      sudoku.Shape
+	givens     sudoku.Grid
+	entries    sudoku.Grid
+	difficulty sudoku.Difficulty
+	variant    sudoku.Variant
+}
+
+// check reads back everything a save holds, failing on anything the game
+// could not have written, such as notes outside the grid's digits, unknown
+// colors, a history that overwrites givens or an unknown mistake mode
+func (saved SavedGame) check() (checkedSave, error) {
+	checked := checkedSave{}
+
+	if saved.Version < 1 || saved.Version > SAVE_VERSION {
+		return checked, fmt.Errorf("unsupported save version: %d", saved.Version)
+	}
+
+	checked.shape = saved.Shape
+
+	if saved.Version < 2 {
+		checked.shape = sudoku.Classic
+	}
+
+	err := checked.shape.Validate()
+
+	if err != nil {
+		return checked, fmt.Errorf("invalid grid in save: %w", err)
+	}
+
+	if len(saved.Notes) != checked.shape.NumCells() || len(saved.Colors) != checked.shape.NumCells() {
+		return checked, fmt.Errorf("save holds notes for %d cells and colors for %d, expected %d", len(saved.Notes), len(saved.Colors), checked.shape.NumCells())
+	}
+
+	checked.givens, err = sudoku.ParseGrid(checked.shape, saved.Givens)
+
+	if err != nil {
+		return checked, fmt.Errorf("invalid givens in save: %w", err)
+	}
+
+	checked.entries, err = sudoku.ParseGrid(checked.shape, saved.Entries)
+
+	if err != nil {
+		return checked, fmt.Errorf("invalid entries in save: %w", err)
+	}
+
+	checked.difficulty, err = sudoku.ParseDifficulty(saved.Difficulty)
+
+	if err != nil {
+		return checked, err
+	}
+
+	constraints, err := sudoku.ParseConstraints(saved.Constraints)
+
+	if err != nil {
+		return checked, fmt.Errorf("invalid constraints in save: %w", err)
+	}
+
+	checked.variant = sudoku.Variant{Cages: saved.Cages, Constraints: constraints}
+
+	for idx := range checked.entries {
+		state := saved.cellState(checked.givens, checked.entries, idx)
+
+		err = sudoku.CheckState(checked.shape, checked.givens, idx, state)
+
+		if err != nil {
+			return checked, fmt.Errorf("invalid cell in save: %w", err)
+		}
+
+		if int(state.Color) >= len(MARK_COLORS) {
+			return checked, fmt.Errorf("invalid color in save for cell %d: %d", idx, state.Color)
+		}
+	}
+
+	for _, commands := range [][]sudoku.Command{saved.Undo, saved.Redo} {
+		err = sudoku.CheckCommands(checked.shape, checked.givens, commands)
+
+		if err != nil {
+			return checked, fmt.Errorf("invalid history in save: %w", err)
+		}
+
+		for _, command := range commands {
+			for _, change := range command.Changes {
+				if int(change.Before.Color) >= len(MARK_COLORS) || int(change.After.Color) >= len(MARK_COLORS) {
+					return checked, fmt.Errorf("invalid color in save history for cell %d", change.Cell)
+				}
+			}
+		}
+	}
+
+	if saved.Settings.MistakeMode < 0 || saved.Settings.MistakeMode >= len(MISTAKE_MODE_NAMES) {
+		return checked, fmt.Errorf("invalid mistake mode in save: %d", saved.Settings.MistakeMode)
+	}
+
+	return checked, nil
+}
+
+// cellState is the state a save records for a cell. Entries leave givens
+// empty, since the givens already hold them.
+func (saved SavedGame) cellState(givens sudoku.Grid, entries sudoku.Grid, idx int) sudoku.CellState {
+	state := sudoku.CellState{
+		Value: entries[idx],
+		Notes: saved.Notes[idx],
+		Color: saved.Colors[idx],
+	}
+
+	if givens[idx] != 0 && state.Value == 0 {
+		state.Value = givens[idx]
+	}
+
+	return state
+}
+
+// Restore loads a saved game. Everything read is checked before the game is
+// touched, so a bad save leaves the current game as it was.
+func (g *Game) Restore(saved SavedGame) error {
+	checked, err := saved.check()
+
+	if err != nil {
+		return err
+	}
+
+	err = g.LoadVariant(checked.shape, checked.givens, checked.variant)
+
+	if err != nil {
+		return err
+	}
+
+	for idx := range checked.entries {
+		g.board.Restore(idx, saved.cellState(checked.givens, checked.entries, idx))
+	}
+
+	g.history = sudoku.RestoreHistory(saved.Undo, saved.Redo)
+	g.seed = saved.Seed
+	g.difficulty = checked.difficulty
+	g.hints = saved.Hints
+	g.elapsed = time.Duration(saved.ElapsedMS) * time.Millisecond
+	g.Settings = saved.Settings
+
+	g.updateInfo()
+	g.updateSettings()
+	g.SetMistakeMode(g.Settings.MistakeMode)
+
+	return nil
+}
+
+// Save writes the game to the save file, or removes the file once the
+// puzzle is solved so there is nothing left to continue
+func (g *Game) Save() error {
+	if g.board.Solved() {
+		return storage.Remove(SAVE_FILE)
+	}
+
+	return storage.WriteJSON(SAVE_FILE, g.Snapshot())
+}
+
+// Resume restores the saved game. A save that cannot be read or restored is
+// removed, so the menu stops offering to continue it.
+func (g *Game) Resume() error {
+	saved := SavedGame{}
+
+	err := storage.ReadJSON(SAVE_FILE, &saved)
+
+	if err == nil {
+		err = g.Restore(saved)
+	}
+
+	if err != nil {
+		if removeErr := storage.Remove(SAVE_FILE); removeErr != nil {
+			log.Printf("Error removing saved game: %s\n", removeErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func HasSavedGame() bool {
+	return storage.Exists(SAVE_FILE)
+}
+
+func (g *Game) Enter(e *Engine) {
+}
+
+// Leave autosaves the game whenever the scene is switched away from
+func (g *Game) Leave(e *Engine) {
+	err := g.Save()
+
+	if err != nil {
+		log.Printf("Error saving game: %s\n", err)
+	}
+}
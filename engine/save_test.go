@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"testing"
+
+	"main/sudoku"
+)
+
+const classicPuzzle = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+// validSave is a save of the classic puzzle with one entry, some notes and a
+// color, as the game would write it
+func validSave() SavedGame {
+	shape := sudoku.Classic
+	entries := sudoku.NewGrid(shape)
+	entries[2] = 4
+
+	saved := SavedGame{
+		Version:    SAVE_VERSION,
+		Shape:      shape,
+		Givens:     classicPuzzle,
+		Entries:    entries.String(),
+		Notes:      make([]sudoku.Candidates, shape.NumCells()),
+		Colors:     make([]byte, shape.NumCells()),
+		Difficulty: sudoku.Easy.String(),
+		Settings:   DefaultSettings(),
+		Undo: []sudoku.Command{{
+			Name:    "place",
+			Changes: []sudoku.Change{{Cell: 2, After: sudoku.CellState{Value: 4}}},
+		}},
+	}
+
+	saved.Notes[3] = sudoku.CandidatesOf(2, 6)
+	saved.Colors[0] = 1
+
+	return saved
+}
+
+func TestSaveCheck(t *testing.T) {
+	checked, err := validSave().check()
+
+	if err != nil {
+		t.Fatalf("checking a valid save: %s", err)
+	}
+
+	if checked.shape != sudoku.Classic || checked.entries[2] != 4 || checked.givens[0] != 5 {
+		t.Errorf("checked save of %s holds entry %d and given %d", checked.shape, checked.entries[2], checked.givens[0])
+	}
+}
+
+// TestSaveCheckRejects checks Restore fails on saves the game could not
+// have written, before the game is touched
+func TestSaveCheckRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(saved *SavedGame)
+	}{
+		{"future version", func(saved *SavedGame) { saved.Version = SAVE_VERSION + 1 }},
+		{"missing notes", func(saved *SavedGame) { saved.Notes = saved.Notes[1:] }},
+		{"unknown difficulty", func(saved *SavedGame) { saved.Difficulty = "Impossible" }},
+		{"notes out of range", func(saved *SavedGame) { saved.Notes[3] = sudoku.Classic.AllCandidates() << 1 }},
+		{"notes on a given", func(saved *SavedGame) { saved.Notes[0] = sudoku.CandidatesOf(1) }},
+		{"entry over a given", func(saved *SavedGame) { saved.Entries = "4" + saved.Entries[1:] }},
+		{"unknown color", func(saved *SavedGame) { saved.Colors[3] = byte(len(MARK_COLORS)) }},
+		{"history over a given", func(saved *SavedGame) { saved.Undo[0].Changes[0].Cell = 0 }},
+		{"history notes out of range", func(saved *SavedGame) {
+			saved.Undo[0].Changes[0].After.Notes = sudoku.Classic.AllCandidates() << 1
+		}},
+		{"history color", func(saved *SavedGame) { saved.Undo[0].Changes[0].After.Color = byte(len(MARK_COLORS)) }},
+		{"redo over a given", func(saved *SavedGame) {
+			saved.Redo = []sudoku.Command{{Name: "place", Changes: []sudoku.Change{{Cell: 1, After: sudoku.CellState{Value: 1}}}}}
+		}},
+		{"negative mistake mode", func(saved *SavedGame) { saved.Settings.MistakeMode = -1 }},
+		{"unknown mistake mode", func(saved *SavedGame) { saved.Settings.MistakeMode = len(MISTAKE_MODE_NAMES) }},
+	}
+
+	for _, test := range tests {
+		saved := validSave()
+		test.modify(&saved)
+
+		if _, err := saved.check(); err == nil {
+			t.Errorf("%s: checked without error", test.name)
+		}
+	}
+}
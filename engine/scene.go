@@ -1,12 +1,23 @@
 package engine
 
-import "github.com/veandco/go-sdl2/sdl"
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
 
 type Scene interface {
 	Setup(*Engine, string, []interface{}) error
 	Delete(*Engine) error
 	GetTitle() string
 
+	Enter(*Engine)
+	Leave(*Engine)
+
+	// Arrange lays the scene out for the size of the window and the
+	// engine's scale
+	Arrange(*Engine)
+
 	InsertWidget(Widget) error
 	RenderWidgets(*Engine) error
 	ContainsWidget(string) bool
@@ -15,6 +26,132 @@ type Scene interface {
 
 	Hover(*Engine, sdl.Point)
 	Click(*Engine, sdl.Point)
+	Input(*Engine, byte, byte)
+	TextInput(*Engine, string)
 
 	Active() *bool
 }
+
+// SceneBase holds the title, widgets and active flag every scene has, along
+// with the Scene methods that only look after the widgets or pass events on
+// to them. Scenes embed it and replace the methods they do more in.
+type SceneBase struct {
+	Title string
+
+	Widgets map[string]Widget
+
+	isActive bool
+}
+
+// setupBase names a new scene and empties its widgets, refusing a title
+// another scene of the engine already has
+func (b *SceneBase) setupBase(e *Engine, title string) error {
+	_, ok := e.Scenes[title]
+
+	if ok {
+		return fmt.Errorf("scene with same title already exists: %s", title)
+	}
+
+	b.Title = title
+	b.Widgets = map[string]Widget{}
+	b.isActive = false
+
+	return nil
+}
+
+func (b *SceneBase) Delete(e *Engine) error {
+	return e.DeleteScene(b.Title)
+}
+
+func (b *SceneBase) GetTitle() string {
+	return b.Title
+}
+
+func (b *SceneBase) Enter(e *Engine) {
+}
+
+func (b *SceneBase) Leave(e *Engine) {
+}
+
+func (b *SceneBase) InsertWidget(widget Widget) error {
+	_, ok := b.Widgets[widget.GetWidgetID()]
+
+	if ok {
+		return fmt.Errorf("widget already exists: %s", widget.GetWidgetID())
+	}
+
+	b.Widgets[widget.GetWidgetID()] = widget
+
+	return nil
+}
+
+func (b *SceneBase) RenderWidgets(e *Engine) error {
+	for _, widget := range b.Widgets {
+		err := widget.Draw(e)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *SceneBase) ContainsWidget(widgetID string) bool {
+	_, ok := b.Widgets[widgetID]
+
+	return ok
+}
+
+// DeleteWidget removes a widget, leaving the IDs of the rest alone since
+// they are the keys the scene holds them under
+func (b *SceneBase) DeleteWidget(widgetID string) error {
+	if !b.ContainsWidget(widgetID) {
+		return fmt.Errorf("no widget exists with ID: %s", widgetID)
+	}
+
+	delete(b.Widgets, widgetID)
+
+	return nil
+}
+
+func (b *SceneBase) GetWidgetIDs() []string {
+	widgetIDs := []string{}
+
+	for id := range b.Widgets {
+		widgetIDs = append(widgetIDs, id)
+	}
+
+	return widgetIDs
+}
+
+func (b *SceneBase) Hover(e *Engine, pos sdl.Point) {
+	if b.isActive {
+		for _, widget := range b.Widgets {
+			widget.Hover(e, pos)
+		}
+	}
+}
+
+func (b *SceneBase) Click(e *Engine, pos sdl.Point) {
+	if b.isActive {
+		for _, widget := range b.Widgets {
+			widget.Click(e, pos)
+		}
+	}
+}
+
+func (b *SceneBase) Input(e *Engine, action byte, pressed byte) {
+	if b.isActive {
+		for _, widget := range b.Widgets {
+			widget.Input(e, action, pressed)
+		}
+	}
+}
+
+func (b *SceneBase) TextInput(e *Engine, text string) {
+}
+
+func (b *SceneBase) Active() *bool {
+	return &b.isActive
+}
@@ -0,0 +1,320 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"main/storage"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// SCENE_DIR holds the scene files, which are JSON files named *.json
+const SCENE_DIR = "scenes"
+
+// SceneFile describes a scene to build without recompiling: its widgets,
+// how they are laid out, and what the buttons do when clicked
+type SceneFile struct {
+	Title string `json:"title"`
+
+	// Background fills the window behind the widgets, black by default
+	Background string `json:"background"`
+
+	Layout Node `json:"layout"`
+}
+
+// Node is a piece of a scene file's layout, picked by Type:
+//
+//   - "vstack" and "hstack" stack Items as a Stack does, using Spacing,
+//     Padding, Align and Justify
+//   - "grid" places Items in Columns as a Grid does, using Spacing, Padding
+//     and Square
+//   - "anchor" pins its only item as an Anchor does, using Horizontal,
+//     Vertical, Offset and Padding
+//   - "spacer" takes up room
+//   - "area" takes up room the scene draws in itself, such as the grid
+//   - "label" shows Text, one line per entry
+//   - "button" shows the first line of Text and runs Action when clicked
+//
+// Width and Height are the size in points the node asks of its container,
+// where 0 fills the room given. Name lets the scene find the node once
+// built, so code can fill in its text or its items. Colors are written "#RRGGBB" or
+// "#RRGGBBAA", and Font and FontSize name one of FONT_FILES and FONT_SIZES.
+type Node struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Width  int32  `json:"width"`
+	Height int32  `json:"height"`
+
+	Items      []Node  `json:"items"`
+	Spacing    int32   `json:"spacing"`
+	Padding    []int32 `json:"padding"`
+	Align      string  `json:"align"`
+	Justify    string  `json:"justify"`
+	Columns    int     `json:"columns"`
+	Square     bool    `json:"square"`
+	Horizontal string  `json:"horizontal"`
+	Vertical   string  `json:"vertical"`
+	Offset     []int32 `json:"offset"`
+
+	Text      []string `json:"text"`
+	Color     string   `json:"color"`
+	TextColor string   `json:"text_color"`
+	Font      string   `json:"font"`
+	FontSize  int      `json:"font_size"`
+
+	// Action is a name from the scene's own actions or SCENE_ACTIONS followed, for those that take
+	// one, by a colon and its argument, such as "switch:Main Menu"
+	Action string `json:"action"`
+
+	// Shortcuts name the input actions that also click the button, from
+	// SCENE_SHORTCUTS
+	Shortcuts []string `json:"shortcuts"`
+}
+
+// SceneAction is something a button in a scene file can do, given the text
+// after the colon in its action
+type SceneAction struct {
+	TakesArg bool
+	Run      func(e *Engine, arg string) error
+}
+
+var (
+	// SCENE_ACTIONS maps the names scene files use for actions to them
+	SCENE_ACTIONS = map[string]SceneAction{
+		// switch:<title> moves to the scene with that title
+		"switch": {TakesArg: true, Run: func(e *Engine, arg string) error {
+			return e.Switch(arg)
+		}},
+
+		// open:<path> starts the first puzzle in a file
+		"open": {TakesArg: true, Run: func(e *Engine, arg string) error {
+			return e.OpenFile(arg)
+		}},
+
+		"fullscreen": {Run: func(e *Engine, arg string) error {
+			return e.ToggleFullscreen()
+		}},
+
+		"quit": {Run: func(e *Engine, arg string) error {
+			_, err := sdl.PushEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+
+			return err
+		}},
+	}
+
+	// SCENE_SHORTCUTS maps the names scene files use for input actions to
+	// them
+	SCENE_SHORTCUTS = map[string]byte{
+		"cancel":       CANCEL,
+		"confirm":      CONFIRM,
+		"hint":         HINT,
+		"toggle_notes": TOGGLE_NOTES,
+		"undo":         UNDO,
+		"redo":         REDO,
+	}
+
+	// SCENE_ALIGNS maps the names scene files use for alignments to them
+	SCENE_ALIGNS = map[string]Align{
+		"":       AlignStart,
+		"start":  AlignStart,
+		"center": AlignCenter,
+		"end":    AlignEnd,
+	}
+)
+
+// SceneDirs lists the directories searched for scene files: the scenes
+// folder in the data directory, then one beside the working directory
+func SceneDirs() []string {
+	dirs := []string{}
+
+	if dir, err := storage.Path(SCENE_DIR); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	return append(dirs, SCENE_DIR)
+}
+
+// LoadSceneFiles reads every scene file, skipping files that fail to parse.
+// A title found in more than one directory is read from the first, so a
+// file in the data directory replaces the one shipped with the game.
+func LoadSceneFiles() []SceneFile {
+	scenes := []SceneFile{}
+	seen := map[string]bool{}
+	titles := map[string]bool{}
+
+	for _, dir := range SceneDirs() {
+		abs, err := filepath.Abs(dir)
+
+		if err != nil || seen[abs] {
+			continue
+		}
+
+		seen[abs] = true
+
+		files, err := os.ReadDir(dir)
+
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() || strings.ToLower(filepath.Ext(file.Name())) != ".json" {
+				continue
+			}
+
+			path := filepath.Join(dir, file.Name())
+			data, err := os.ReadFile(path)
+
+			if err != nil {
+				log.Printf("Error reading scene file: %s\n", err)
+				continue
+			}
+
+			scene, err := ParseSceneFile(data)
+
+			if err != nil {
+				log.Printf("Error reading scene file %s: %s\n", path, err)
+				continue
+			}
+
+			if titles[scene.Title] {
+				continue
+			}
+
+			titles[scene.Title] = true
+			scenes = append(scenes, scene)
+		}
+	}
+
+	return scenes
+}
+
+// ParseSceneFile reads a scene file, rejecting fields it does not know so
+// misspellings are caught rather than ignored
+func ParseSceneFile(data []byte) (SceneFile, error) {
+	var scene SceneFile
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&scene)
+
+	if err != nil {
+		return SceneFile{}, err
+	}
+
+	if strings.TrimSpace(scene.Title) == "" {
+		return SceneFile{}, fmt.Errorf("scene has no title")
+	}
+
+	return scene, nil
+}
+
+// ParseColor reads a color written "#RRGGBB", which is opaque, or
+// "#RRGGBBAA". An empty string is the zero color, which widgets replace
+// with their default.
+func ParseColor(text string) (sdl.Color, error) {
+	if text == "" {
+		return sdl.Color{}, nil
+	}
+
+	hex, ok := strings.CutPrefix(text, "#")
+
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return sdl.Color{}, fmt.Errorf("invalid color %q: want #RRGGBB or #RRGGBBAA", text)
+	}
+
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+
+	if err != nil {
+		return sdl.Color{}, fmt.Errorf("invalid color %q: want #RRGGBB or #RRGGBBAA", text)
+	}
+
+	return sdl.Color{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
+
+// TakeSceneFile removes the scene file with a title from files, for the
+// scenes code builds on top of a file
+func TakeSceneFile(files *[]SceneFile, title string) (SceneFile, error) {
+	for i, file := range *files {
+		if file.Title == title {
+			*files = append((*files)[:i], (*files)[i+1:]...)
+			return file, nil
+		}
+	}
+
+	return SceneFile{}, fmt.Errorf("no scene file titled %q in %s", title, strings.Join(SceneDirs(), " or "))
+}
+
+// simpleAction is an action taking no argument that cannot fail
+func simpleAction(run func(e *Engine)) SceneAction {
+	return SceneAction{Run: func(e *Engine, arg string) error {
+		run(e)
+
+		return nil
+	}}
+}
+
+// parseAction checks an action against the actions of the scene, then
+// SCENE_ACTIONS, and returns a function running it
+func parseAction(actions map[string]SceneAction, text string) (func(e *Engine) error, error) {
+	name, arg, hasArg := strings.Cut(text, ":")
+	action, ok := actions[name]
+
+	if !ok {
+		action, ok = SCENE_ACTIONS[name]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", name)
+	}
+
+	if action.TakesArg && arg == "" {
+		return nil, fmt.Errorf("action %q needs an argument, as in %q", name, name+":...")
+	}
+
+	if !action.TakesArg && hasArg {
+		return nil, fmt.Errorf("action %q takes no argument", name)
+	}
+
+	return func(e *Engine) error {
+		return action.Run(e, arg)
+	}, nil
+}
+
+// insets reads padding given as one value for every edge or four for the
+// top, right, bottom and left
+func insets(values []int32) (Insets, error) {
+	switch len(values) {
+	case 0:
+		return Insets{}, nil
+	case 1:
+		return Pad(values[0]), nil
+	case 4:
+		return Insets{Top: values[0], Right: values[1], Bottom: values[2], Left: values[3]}, nil
+	default:
+		return Insets{}, fmt.Errorf("padding takes 1 or 4 values, got %d", len(values))
+	}
+}
+
+// align reads an alignment by name, naming the field at fault
+func align(field string, name string) (Align, error) {
+	a, ok := SCENE_ALIGNS[name]
+
+	if !ok {
+		return AlignStart, fmt.Errorf("invalid %s %q: want start, center or end", field, name)
+	}
+
+	return a, nil
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+
+	"main/storage"
+)
+
+const SETTINGS_FILE = "settings.json"
+
+// Settings holds the player's preferences for a game
+type Settings struct {
+	// AutoClearNotes removes a placed digit from the notes of its peers
+	AutoClearNotes bool `json:"auto_clear_notes"`
+
+	// HighlightPeers shades the row, column and box of the selected cell
+	HighlightPeers bool `json:"highlight_peers"`
+
+	// HighlightSameDigit shades every cell holding the selected digit,
+	// either placed or as a note
+	HighlightSameDigit bool `json:"highlight_same_digit"`
+
+	// MistakeMode is one of the MISTAKES_ modes
+	MistakeMode int `json:"mistake_mode"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		AutoClearNotes:     true,
+		HighlightPeers:     true,
+		HighlightSameDigit: true,
+		MistakeMode:        MISTAKES_CONFLICTS,
+	}
+}
+
+// LoadSettings reads the player's preferences, keeping the defaults for a
+// missing file and for any setting the file leaves out
+func LoadSettings() (Settings, error) {
+	settings := DefaultSettings()
+
+	err := storage.ReadJSON(SETTINGS_FILE, &settings)
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return DefaultSettings(), err
+	}
+
+	if settings.MistakeMode < 0 || settings.MistakeMode >= len(MISTAKE_MODE_NAMES) {
+		settings.MistakeMode = MISTAKES_CONFLICTS
+	}
+
+	return settings, nil
+}
+
+// onOff names a setting along with whether it is turned on
+func onOff(name string, on bool) string {
+	if on {
+		return name + " On"
+	}
+
+	return name + " Off"
+}
+
+// updateSettings shows the settings on their buttons
+func (g *Game) updateSettings() {
+	g.autoClearButton.Text = onOff("Clear", g.Settings.AutoClearNotes)
+	g.peersButton.Text = onOff("Peers", g.Settings.HighlightPeers)
+	g.sameDigitButton.Text = onOff("Same", g.Settings.HighlightSameDigit)
+}
+
+// saveSettings applies a changed setting and writes the settings, so the
+// next game starts with them too
+func (g *Game) saveSettings() {
+	g.updateSettings()
+	g.updateCells()
+
+	err := storage.WriteJSON(SETTINGS_FILE, g.Settings)
+
+	if err != nil {
+		log.Printf("Error saving settings: %s\n", err)
+	}
+}
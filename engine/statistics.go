@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"main/sudoku"
+)
+
+const STATS_ROW_FORMAT = "%-10s %7s %8s %8s %8s %8s %7s %7s"
+
+// Statistics shows the per-difficulty totals kept in the stats file
+type Statistics struct {
+	FileScene
+
+	rows []*Label
+}
+
+func (s *Statistics) Setup(e *Engine, title string, args []interface{}) error {
+	*s = Statistics{}
+
+	err := s.setupBase(e, title)
+
+	if err != nil {
+		return err
+	}
+
+	return e.InsertScene(s)
+}
+
+// Enter reloads the stats file so games finished since the last visit show
+func (s *Statistics) Enter(e *Engine) {
+	stats, err := LoadStats()
+
+	if err != nil {
+		log.Printf("Error loading statistics: %s\n", err)
+	}
+
+	for i, row := range s.rows {
+		difficulty := sudoku.Difficulties()[i]
+		row.Text = []string{statsRow(difficulty, stats.For(difficulty))}
+	}
+}
+
+func statsRow(difficulty sudoku.Difficulty, ds DifficultyStats) string {
+	times := [3]string{"--:--", "--:--", "--:--"}
+
+	for i, d := range []time.Duration{ds.Best(), ds.Average(), ds.Median()} {
+		if ds.Finished > 0 {
+			times[i] = formatDuration(d)
+		}
+	}
+
+	return fmt.Sprintf(STATS_ROW_FORMAT,
+		difficulty,
+		fmt.Sprint(ds.Started),
+		fmt.Sprint(ds.Finished),
+		times[0], times[1], times[2],
+		fmt.Sprint(ds.CurrentStreak),
+		fmt.Sprint(ds.BestStreak),
+	)
+}
+
+// NewStatistics builds the statistics from its scene file, filling the rows
+// node with a row for each difficulty
+func (s *Statistics) NewStatistics(e *Engine, file SceneFile) error {
+	err := s.NewFileScene(e, file)
+
+	if err != nil {
+		return err
+	}
+
+	header, err := named[*Label](&s.FileScene, "header")
+
+	if err != nil {
+		return err
+	}
+
+	header.Text = []string{fmt.Sprintf(STATS_ROW_FORMAT, "", "Started", "Finished", "Best", "Average", "Median", "Streak", "Record")}
+
+	rows, err := named[*Stack](&s.FileScene, "rows")
+
+	if err != nil {
+		return err
+	}
+
+	// Add one row per difficulty, filled in on entering the scene
+	for range sudoku.Difficulties() {
+		row := &Label{}
+
+		err = row.SetupWith(s, LabelOptions{
+			Color:    LIBRARY_ROW_COLOR,
+			Text:     []string{""},
+			FontName: "lotuscoder_normal",
+			FontSize: 14,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		s.rows = append(s.rows, row)
+		rows.Items = append(rows.Items, Fixed(row, 0, 40))
+	}
+
+	s.Arrange(e)
+
+	return nil
+}
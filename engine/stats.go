@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"time"
+
+	"main/storage"
+	"main/sudoku"
+)
+
+const STATS_FILE = "stats.json"
+
+// DifficultyStats totals the games played at one difficulty
+type DifficultyStats struct {
+	Started       int     `json:"started"`
+	Finished      int     `json:"finished"`
+	TimesMS       []int64 `json:"times_ms"`
+	CurrentStreak int     `json:"current_streak"`
+	BestStreak    int     `json:"best_streak"`
+}
+
+func (ds DifficultyStats) Best() time.Duration {
+	if len(ds.TimesMS) == 0 {
+		return 0
+	}
+
+	return time.Duration(slices.Min(ds.TimesMS)) * time.Millisecond
+}
+
+func (ds DifficultyStats) Average() time.Duration {
+	if len(ds.TimesMS) == 0 {
+		return 0
+	}
+
+	total := int64(0)
+
+	for _, ms := range ds.TimesMS {
+		total += ms
+	}
+
+	return time.Duration(total/int64(len(ds.TimesMS))) * time.Millisecond
+}
+
+func (ds DifficultyStats) Median() time.Duration {
+	if len(ds.TimesMS) == 0 {
+		return 0
+	}
+
+	times := slices.Clone(ds.TimesMS)
+	slices.Sort(times)
+
+	mid := len(times) / 2
+
+	if len(times)%2 == 0 {
+		return time.Duration((times[mid-1]+times[mid])/2) * time.Millisecond
+	}
+
+	return time.Duration(times[mid]) * time.Millisecond
+}
+
+// GameStats holds the statistics for every difficulty, keyed by its name
+type GameStats struct {
+	Difficulties map[string]DifficultyStats `json:"difficulties"`
+
+	// InProgress names the difficulty of a started game that has not been
+	// finished, so abandoning it for a new one can end the streak
+	InProgress string `json:"in_progress,omitempty"`
+}
+
+// LoadStats reads the statistics, treating a missing file as no games played
+func LoadStats() (GameStats, error) {
+	stats := GameStats{}
+
+	err := storage.ReadJSON(STATS_FILE, &stats)
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return GameStats{Difficulties: map[string]DifficultyStats{}}, err
+	}
+
+	if stats.Difficulties == nil {
+		stats.Difficulties = map[string]DifficultyStats{}
+	}
+
+	return stats, nil
+}
+
+func (gs GameStats) For(difficulty sudoku.Difficulty) DifficultyStats {
+	return gs.Difficulties[difficulty.String()]
+}
+
+func updateStats(update func(stats *GameStats)) error {
+	stats, err := LoadStats()
+
+	if err != nil {
+		return err
+	}
+
+	update(&stats)
+
+	return storage.WriteJSON(STATS_FILE, stats)
+}
+
+func recordGameStart(difficulty sudoku.Difficulty) error {
+	return updateStats(func(stats *GameStats) {
+		if abandoned, ok := stats.Difficulties[stats.InProgress]; ok {
+			abandoned.CurrentStreak = 0
+			stats.Difficulties[stats.InProgress] = abandoned
+		}
+
+		ds := stats.Difficulties[difficulty.String()]
+		ds.Started++
+		stats.Difficulties[difficulty.String()] = ds
+		stats.InProgress = difficulty.String()
+	})
+}
+
+func recordGameFinish(difficulty sudoku.Difficulty, elapsed time.Duration) error {
+	return updateStats(func(stats *GameStats) {
+		ds := stats.Difficulties[difficulty.String()]
+		ds.Finished++
+		ds.TimesMS = append(ds.TimesMS, elapsed.Milliseconds())
+		ds.CurrentStreak++
+		ds.BestStreak = max(ds.BestStreak, ds.CurrentStreak)
+		stats.Difficulties[difficulty.String()] = ds
+
+		if stats.InProgress == difficulty.String() {
+			stats.InProgress = ""
+		}
+	})
+}
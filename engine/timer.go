@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"log"
+	"time"
+)
+
+// MAX_TICK caps the seconds one frame adds to the clock, so a stalled frame
+// such as one loading a large puzzle is not charged to the player
+const MAX_TICK = 0.25
+
+// tick advances the clock by the last frame while the game is on screen,
+// in focus and not yet finished. The clock stays stopped once the puzzle is
+// solved, even if undo then reopens it.
+func (g *Game) tick(e *Engine) {
+	running := g.isActive && e.Focused && !g.finished
+
+	if running {
+		g.elapsed += time.Duration(min(e.FrameTime, MAX_TICK) * float64(time.Second))
+	}
+
+	g.timerLabel.Text = []string{formatDuration(g.elapsed)}
+
+	if !running && !g.finished {
+		g.timerLabel.Text[0] += " (Paused)"
+	}
+}
+
+// startGame counts a freshly loaded puzzle towards the statistics
+func (g *Game) startGame() {
+	err := recordGameStart(g.difficulty)
+
+	if err != nil {
+		log.Printf("Error recording game start: %s\n", err)
+	}
+}
+
+// finishGame records a solve once, however often undo and redo revisit it
+func (g *Game) finishGame() {
+	if g.finished {
+		return
+	}
+
+	g.finished = true
+
+	key, err := recordKey(g.board.Shape(), g.board.Givens(), g.board.Variant())
+
+	if err == nil {
+		err = recordSolve(key, g.elapsed)
+	}
+
+	if err != nil {
+		log.Printf("Error recording solve: %s\n", err)
+	}
+
+	err = recordGameFinish(g.difficulty, g.elapsed)
+
+	if err != nil {
+		log.Printf("Error recording game finish: %s\n", err)
+	}
+}
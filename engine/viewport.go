@@ -0,0 +1,160 @@
+package engine
+
+import "github.com/veandco/go-sdl2/sdl"
+
+const (
+	// MULTI_CELL_SIZE is the size in points of a cell when a layout of grids
+	// is loaded, which zooming changes by ZOOM_STEP up to MAX_CELL_SIZE
+	MULTI_CELL_SIZE = int32(36)
+	MAX_CELL_SIZE   = int32(60)
+	ZOOM_STEP       = int32(6)
+
+	// SCROLL_STEP is how many points a turn of the mouse wheel scrolls the
+	// canvas
+	SCROLL_STEP = int32(40)
+)
+
+// canvasExtent is the width of cells laid side by side with boxes of
+// boxCells, at the current zoom
+func (g *Game) canvasExtent(cells int, boxCells int) int32 {
+	return int32(cells)*g.cellSize + int32(cells-1)*g.cellGap + int32(cells/boxCells-1)*g.boxGap
+}
+
+// maxScroll is how far the canvas can scroll before its far edge reaches
+// the far side of the grid square
+func (g *Game) maxScroll() sdl.Point {
+	return sdl.Point{
+		X: max(0, g.canvasExtent(g.shape.Width(), g.shape.BoxWidth)-g.square.W),
+		Y: max(0, g.canvasExtent(g.shape.Height(), g.shape.BoxHeight)-g.square.H),
+	}
+}
+
+// placeCells moves the cells to their place on the canvas, centering it in
+// the grid square when it fits and scrolling it otherwise. Cells not wholly
+// inside the square are hidden so they do not spill over the panel.
+func (g *Game) placeCells() {
+	shape := g.shape
+	limit := g.maxScroll()
+	g.scroll.X = min(max(g.scroll.X, 0), limit.X)
+	g.scroll.Y = min(max(g.scroll.Y, 0), limit.Y)
+
+	left := g.square.X + max(0, g.square.W-g.canvasExtent(shape.Width(), shape.BoxWidth))/2 - g.scroll.X
+	top := g.square.Y + max(0, g.square.H-g.canvasExtent(shape.Height(), shape.BoxHeight))/2 - g.scroll.Y
+
+	for idx, cell := range g.cells {
+		if cell == nil {
+			continue
+		}
+
+		row, col := shape.RowCol(idx)
+
+		cell.Rect = sdl.Rect{
+			X: left + int32(col)*(g.cellSize+g.cellGap) + int32(col/shape.BoxWidth)*g.boxGap,
+			Y: top + int32(row)*(g.cellSize+g.cellGap) + int32(row/shape.BoxHeight)*g.boxGap,
+			W: g.cellSize,
+			H: g.cellSize,
+		}
+
+		cell.FontSize = g.fontSize(g.cellSize / 2)
+		cell.NoteFontSize = g.fontSize(g.cellSize / int32(shape.BoxHeight))
+		cell.RegionGap = g.cellGap
+
+		inside, ok := g.square.Intersect(&cell.Rect)
+		*cell.Visible() = ok && inside == cell.Rect
+	}
+}
+
+// Zoom grows or shrinks the cells of a layout of grids by steps, between
+// the size that fits the whole canvas in the square and MAX_CELL_SIZE
+func (g *Game) Zoom(steps int32) {
+	if !g.shape.Multi() {
+		return
+	}
+
+	size := min(max(g.cellSize+steps*scaled(ZOOM_STEP, g.scale), g.fitSize), max(scaled(MAX_CELL_SIZE, g.scale), g.fitSize))
+
+	if size == g.cellSize {
+		return
+	}
+
+	// Keep the middle of the view where it was
+	middle := sdl.Point{X: g.scroll.X + g.square.W/2, Y: g.scroll.Y + g.square.H/2}
+	g.scroll.X = middle.X*size/g.cellSize - g.square.W/2
+	g.scroll.Y = middle.Y*size/g.cellSize - g.square.H/2
+	g.cellSize = size
+
+	g.viewChanged()
+}
+
+// Scroll moves the view over the canvas of a layout of grids by pixels
+func (g *Game) Scroll(dx int32, dy int32) {
+	if !g.shape.Multi() {
+		return
+	}
+
+	g.scroll.X += dx
+	g.scroll.Y += dy
+
+	g.viewChanged()
+}
+
+// Wheel scrolls the canvas with the mouse wheel, sideways while Shift is
+// held, or zooms it while Ctrl is held. Presses stand for turns up or
+// right and releases for turns down or left.
+func (g *Game) Wheel(e *Engine, action byte, pressed byte) {
+	step := int32(1)
+
+	if pressed == RELEASED {
+		step = -1
+	}
+
+	distance := step * scaled(SCROLL_STEP, g.scale)
+
+	switch {
+	case action == HORIZ_SCROLL:
+		g.Scroll(distance, 0)
+	case e.Modifiers&MOD_CTRL != 0:
+		g.Zoom(step)
+	case e.Modifiers&MOD_SHIFT != 0:
+		g.Scroll(-distance, 0)
+	default:
+		g.Scroll(0, -distance)
+	}
+}
+
+// scrollTo scrolls just far enough to bring a cell wholly into view
+func (g *Game) scrollTo(idx int) {
+	if idx < 0 || !g.shape.Multi() {
+		return
+	}
+
+	rect := g.cells[idx].Rect
+	before := g.scroll
+
+	square := g.square
+
+	if rect.X < square.X {
+		g.scroll.X -= square.X - rect.X
+	} else if rect.X+rect.W > square.X+square.W {
+		g.scroll.X += rect.X + rect.W - (square.X + square.W)
+	}
+
+	if rect.Y < square.Y {
+		g.scroll.Y -= square.Y - rect.Y
+	} else if rect.Y+rect.H > square.Y+square.H {
+		g.scroll.Y += rect.Y + rect.H - (square.Y + square.H)
+	}
+
+	if g.scroll != before {
+		g.viewChanged()
+	}
+}
+
+// viewChanged moves the cells after a zoom or scroll, along with the lines
+// and outlines drawn from their positions
+func (g *Game) viewChanged() {
+	g.placeCells()
+	g.updateCages()
+	g.updateOverlay()
+	g.updateCells()
+}
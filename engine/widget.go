@@ -1,9 +1,15 @@
 package engine
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// Widget is an element of a scene. Setup takes the widget's options, such
+// as a ButtonOptions, as its only argument so scenes can be built by
+// generic loaders; code that knows the widget calls its SetupWith instead.
 type Widget interface {
 	Setup(Scene, []interface{}) error
 	Delete(Scene) error
@@ -20,4 +26,67 @@ type Widget interface {
 
 	Hover(*Engine, sdl.Point)
 	Click(*Engine, sdl.Point)
+	Input(*Engine, byte, byte)
+}
+
+const (
+	// DEFAULT_FONT and DEFAULT_FONT_SIZE are what widgets write their text
+	// in when their options leave the font out
+	DEFAULT_FONT      = "lotuscoder_normal"
+	DEFAULT_FONT_SIZE = 24
+)
+
+// DEFAULT_TEXT_COLOR is the color of text when the options leave it out
+var DEFAULT_TEXT_COLOR = sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+
+// widgetOptions unpacks the options generic callers pass to Widget.Setup,
+// which must be a single value of, or pointer to, the widget's option type
+func widgetOptions[T any](widget string, args []interface{}) (T, error) {
+	var opts T
+
+	if len(args) != 1 {
+		return opts, fmt.Errorf("%s setup takes one %T, got %d arguments", widget, opts, len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case T:
+		return arg, nil
+	case *T:
+		if arg != nil {
+			return *arg, nil
+		}
+	}
+
+	return opts, fmt.Errorf("%s setup takes a %T, got %T", widget, opts, args[0])
+}
+
+// nextWidgetID names a new widget after its kind and the first number no
+// widget of that kind in the scene is using. IDs are never reused while
+// their widget is in the scene, since they are its key there.
+func nextWidgetID(s Scene, prefix string) string {
+	for i := 0; ; i++ {
+		id := fmt.Sprintf("%s_%d", prefix, i)
+
+		if !s.ContainsWidget(id) {
+			return id
+		}
+	}
+}
+
+// checkText validates the size and font shared by the options of widgets
+// showing text, naming the field at fault
+func checkText(widget string, size sdl.Point, fontName string, fontSize int) error {
+	if size.X < 0 || size.Y < 0 {
+		return fmt.Errorf("invalid %s option Size: %dx%d is negative", widget, size.X, size.Y)
+	}
+
+	if _, ok := FONT_FILES[fontName]; !ok {
+		return fmt.Errorf("invalid %s option FontName: no font is named %q", widget, fontName)
+	}
+
+	if !slices.Contains(FONT_SIZES, fontSize) {
+		return fmt.Errorf("invalid %s option FontSize: fonts are not loaded in size %d", widget, fontSize)
+	}
+
+	return nil
 }
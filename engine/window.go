@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+const (
+	// WINDOW_WIDTH and WINDOW_HEIGHT are the size in points the window
+	// opens at, which is also the smallest it can be made
+	WINDOW_WIDTH  = int32(800)
+	WINDOW_HEIGHT = int32(600)
+
+	// The UI scale is kept between MIN_UI_SCALE and MAX_UI_SCALE so text
+	// stays within the sizes the fonts are loaded in
+	MIN_UI_SCALE = 0.5
+	MAX_UI_SCALE = 4.0
+
+	// BASE_DPI is the display density drawn at a scale of 1
+	BASE_DPI = 96.0
+)
+
+// ClampScale keeps a UI scale within the supported range, treating 0 as 1
+func ClampScale(scale float64) float64 {
+	if scale == 0 {
+		return 1
+	}
+
+	return min(max(scale, MIN_UI_SCALE), MAX_UI_SCALE)
+}
+
+// DisplayScale guesses a UI scale for a display from its density, in
+// quarter steps, shrinking it if a window of the default size would not
+// fit on the display
+func DisplayScale(display int) float64 {
+	_, hdpi, _, err := sdl.GetDisplayDPI(display)
+
+	if err != nil || hdpi <= 0 {
+		return 1
+	}
+
+	scale := math.Round(float64(hdpi)/BASE_DPI*4) / 4
+	bounds, err := sdl.GetDisplayUsableBounds(display)
+
+	if err == nil {
+		scale = min(scale, float64(bounds.W)/float64(WINDOW_WIDTH), float64(bounds.H)/float64(WINDOW_HEIGHT))
+		scale = math.Floor(scale*4) / 4
+	}
+
+	return ClampScale(scale)
+}
+
+// WindowSize is the size in pixels of a window of the default size at a
+// UI scale
+func WindowSize(scale float64) (int32, int32) {
+	scale = ClampScale(scale)
+
+	return scaled(WINDOW_WIDTH, scale), scaled(WINDOW_HEIGHT, scale)
+}
+
+// Resized lays every scene out again after the window changes size
+func (e *Engine) Resized() {
+	for _, scene := range e.Scenes {
+		scene.Arrange(e)
+	}
+
+	if e.CurrentScene != nil {
+		e.CurrentScene.Hover(e, e.MousePos)
+	}
+}
+
+// ToggleFullscreen switches between a window and filling the desktop. The
+// window reports its new size afterwards, which lays the scenes out again.
+func (e *Engine) ToggleFullscreen() error {
+	flags := uint32(sdl.WINDOW_FULLSCREEN_DESKTOP)
+
+	if e.Window.GetFlags()&sdl.WINDOW_FULLSCREEN != 0 {
+		flags = 0
+	}
+
+	return e.Window.SetFullscreen(flags)
+}
+
+// NearestFontSize picks the loaded font size closest to size pixels, the
+// smaller one when two are as close
+func NearestFontSize(size float64) int {
+	best := FONT_SIZES[0]
+
+	for _, font_size := range FONT_SIZES {
+		if math.Abs(float64(font_size)-size) < math.Abs(float64(best)-size) {
+			best = font_size
+		}
+	}
+
+	return best
+}
+
+// font finds the loaded font for text of font_size points, drawn at the
+// loaded size nearest to it at the engine's scale
+func (e *Engine) font(font_name string, font_size int) (*ttf.Font, error) {
+	pixels := NearestFontSize(float64(font_size) * e.Scale)
+	font, ok := e.Fonts[pixels][font_name]
+
+	if !ok {
+		return nil, fmt.Errorf("failed to load font %s of size %d", font_name, pixels)
+	}
+
+	return font, nil
+}
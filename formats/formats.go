@@ -0,0 +1,279 @@
+package formats
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"main/sudoku"
+)
+
+type Format int
+
+const (
+	// FormatLine is one puzzle written as a character per cell
+	FormatLine Format = iota
+	// FormatSDM is one puzzle per line, written as in FormatLine
+	FormatSDM
+	// FormatSDK is the SadMan Sudoku grid with optional #-prefixed metadata
+	// and a [Regions] section for jigsaw puzzles
+	FormatSDK
+	// FormatSS is the Simple Sudoku grid with box borders
+	FormatSS
+	// FormatPM is a HoDoKu style pencil mark grid
+	FormatPM
+)
+
+var formatNames = [...]string{"line", "sdm", "sdk", "ss", "pm"}
+
+func (f Format) String() string {
+	if f < FormatLine || f > FormatPM {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+
+	return formatNames[f]
+}
+
+// Puzzle is a grid read from or written to a file, with the pencil marks and
+// metadata the format carries
+type Puzzle struct {
+	Shape  sudoku.Shape
+	Givens sudoku.Grid
+
+	// Notes holds the candidates of every empty cell when HasNotes is set,
+	// and Entries the digits placed in cells that are not givens, which
+	// only the pencil mark format keeps
+	Notes    []sudoku.Candidates
+	Entries  sudoku.Grid
+	HasNotes bool
+
+	Name       string
+	Author     string
+	Comment    string
+	Source     string
+	Difficulty string
+}
+
+// ParseError locates a problem in the input by 1-based line and column
+type ParseError struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+func parseErrorf(line int, col int, format string, args ...interface{}) *ParseError {
+	return &ParseError{Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// FormatForPath picks a format from a file extension, reporting false when
+// the extension is not recognized
+func FormatForPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sdm":
+		return FormatSDM, true
+	case ".sdk":
+		return FormatSDK, true
+	case ".ss":
+		return FormatSS, true
+	case ".pm":
+		return FormatPM, true
+	default:
+		return FormatLine, false
+	}
+}
+
+// Detect guesses the format of text from its layout
+func Detect(text string) Format {
+	lines := splitLines(text)
+	rows := 0
+	width := 0
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Border lines look alike across the boxed formats so the rows decide
+		if trimmed == "" || strings.Contains(trimmed, "-") && strings.Trim(trimmed, ".:'-+*|") == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "[") {
+			return FormatSDK
+		}
+
+		// Pencil mark rows separate cells with spaces, so even the smallest
+		// grid has a field per cell, and each box several fields where a
+		// Simple Sudoku row writes a box as one
+		fields := 0
+		spaced := false
+
+		for _, box := range strings.Split(trimmed, "|") {
+			n := len(strings.Fields(box))
+			fields += n
+			spaced = spaced || n > 1
+		}
+
+		if spaced && fields >= sudoku.Shapes()[0].Size() {
+			return FormatPM
+		}
+
+		if strings.Contains(trimmed, "|") {
+			return FormatSS
+		}
+
+		if rows == 0 {
+			width = len(strings.Fields(trimmed)[0])
+		}
+
+		rows++
+	}
+
+	// A grid has as many rows as cells in a row, where a list has a line per puzzle
+	switch {
+	case rows > 1 && rows == width:
+		return FormatSDK
+	case rows > 1:
+		return FormatSDM
+	default:
+		return FormatLine
+	}
+}
+
+func Read(r io.Reader, format Format) ([]Puzzle, error) {
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(data)
+
+	switch format {
+	case FormatLine, FormatSDM:
+		return readSDM(text)
+	case FormatSDK:
+		return readSDK(text)
+	case FormatSS:
+		return readSS(text)
+	case FormatPM:
+		return readPM(text)
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// ReadString reads puzzles from text in whichever format it appears to be
+func ReadString(text string) ([]Puzzle, error) {
+	return Read(strings.NewReader(text), Detect(text))
+}
+
+func ReadFile(path string) ([]Puzzle, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	format, ok := FormatForPath(path)
+
+	if !ok {
+		format = Detect(string(data))
+	}
+
+	puzzles, err := Read(bytes.NewReader(data), format)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return puzzles, nil
+}
+
+func Write(w io.Writer, format Format, puzzles []Puzzle) error {
+	if format != FormatSDM && len(puzzles) != 1 {
+		return fmt.Errorf("%s format holds exactly one puzzle, got %d", format, len(puzzles))
+	}
+
+	// The boxed formats draw regular boxes, which would misplace the regions
+	if (format == FormatSS || format == FormatPM) && puzzles[0].Shape.Jigsaw() {
+		return fmt.Errorf("%s format cannot hold jigsaw regions", format)
+	}
+
+	// Only the line formats can write a grid at a time
+	if format != FormatLine && format != FormatSDM && puzzles[0].Shape.Multi() {
+		return fmt.Errorf("%s format cannot hold the %s layout", format, puzzles[0].Shape)
+	}
+
+	var text string
+
+	switch format {
+	case FormatLine, FormatSDM:
+		text = writeSDM(puzzles)
+	case FormatSDK:
+		text = writeSDK(puzzles[0])
+	case FormatSS:
+		text = writeSS(puzzles[0])
+	case FormatPM:
+		text = writePM(puzzles[0])
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+
+	_, err := io.WriteString(w, text)
+
+	return err
+}
+
+// WriteString renders puzzles in a format, for copying to the clipboard
+func WriteString(format Format, puzzles []Puzzle) (string, error) {
+	var sb strings.Builder
+
+	err := Write(&sb, format, puzzles)
+
+	return sb.String(), err
+}
+
+func WriteFile(path string, puzzles []Puzzle) error {
+	format, ok := FormatForPath(path)
+
+	if !ok && len(puzzles) > 1 {
+		format = FormatSDM
+	}
+
+	var buf bytes.Buffer
+
+	err := Write(&buf, format, puzzles)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// cellValue decodes a grid character, where '.' and '0' mark an empty cell
+func cellValue(shape sudoku.Shape, ch byte) (byte, bool) {
+	value, ok := sudoku.ParseSymbol(ch)
+
+	return value, ok && int(value) <= shape.Size()
+}
+
+// newPuzzle starts a puzzle once the size of its grid is known
+func newPuzzle(shape sudoku.Shape) Puzzle {
+	return Puzzle{
+		Shape:   shape,
+		Givens:  sudoku.NewGrid(shape),
+		Notes:   make([]sudoku.Candidates, shape.NumCells()),
+		Entries: sudoku.NewGrid(shape),
+	}
+}
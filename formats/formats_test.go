@@ -0,0 +1,220 @@
+package formats
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"main/sudoku"
+)
+
+const classicGivens = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+func parseGivens(t *testing.T, shape sudoku.Shape, text string) sudoku.Grid {
+	t.Helper()
+
+	givens, err := sudoku.ParseGrid(shape, text)
+
+	if err != nil {
+		t.Fatalf("parsing givens: %s", err)
+	}
+
+	return givens
+}
+
+// roundTrip writes puzzles in a format and reads them back in the format
+// the text is detected as, which must be the same one
+func roundTrip(t *testing.T, format Format, puzzles []Puzzle) []Puzzle {
+	t.Helper()
+
+	text, err := WriteString(format, puzzles)
+
+	if err != nil {
+		t.Fatalf("writing %s: %s", format, err)
+	}
+
+	if detected := Detect(text); detected != format && !(format == FormatLine && detected == FormatSDM) {
+		t.Fatalf("%s text detected as %s:\n%s", format, detected, text)
+	}
+
+	read, err := Read(strings.NewReader(text), format)
+
+	if err != nil {
+		t.Fatalf("reading %s: %s\n%s", format, err, text)
+	}
+
+	if len(read) != len(puzzles) {
+		t.Fatalf("read %d puzzles, wrote %d", len(read), len(puzzles))
+	}
+
+	return read
+}
+
+func checkGivens(t *testing.T, got Puzzle, want Puzzle) {
+	t.Helper()
+
+	if got.Shape != want.Shape {
+		t.Errorf("shape %s, want %s", got.Shape, want.Shape)
+	}
+
+	if !slices.Equal(got.Givens, want.Givens) {
+		t.Errorf("givens %s, want %s", got.Givens, want.Givens)
+	}
+}
+
+func TestRoundTripLine(t *testing.T) {
+	shapes := append(sudoku.Shapes(), sudoku.MultiShapes()...)
+
+	for _, shape := range shapes {
+		puzzle := newPuzzle(shape)
+
+		// Fill a scattering of cells so each position is checked
+		for idx := range puzzle.Givens {
+			if idx%3 == 0 {
+				puzzle.Givens[idx] = byte(idx%shape.Size() + 1)
+			}
+		}
+
+		read := roundTrip(t, FormatLine, []Puzzle{puzzle})
+		checkGivens(t, read[0], puzzle)
+	}
+}
+
+func TestRoundTripSDM(t *testing.T) {
+	first := newPuzzle(sudoku.Classic)
+	first.Givens = parseGivens(t, sudoku.Classic, classicGivens)
+
+	jigsaw, err := sudoku.Shapes()[0].ParseRegions("1122112233443344")
+
+	if err != nil {
+		t.Fatalf("parsing regions: %s", err)
+	}
+
+	second := newPuzzle(jigsaw)
+	second.Givens[0] = 1
+	second.Givens[15] = 4
+
+	read := roundTrip(t, FormatSDM, []Puzzle{first, second})
+	checkGivens(t, read[0], first)
+	checkGivens(t, read[1], second)
+}
+
+func TestRoundTripSDK(t *testing.T) {
+	jigsaw, err := sudoku.Shapes()[0].ParseRegions("1111222233334444")
+
+	if err != nil {
+		t.Fatalf("parsing regions: %s", err)
+	}
+
+	for _, shape := range []sudoku.Shape{sudoku.Classic, jigsaw} {
+		puzzle := newPuzzle(shape)
+		puzzle.Givens[1] = 2
+		puzzle.Name = "Test"
+		puzzle.Author = "Someone"
+		puzzle.Comment = "A comment"
+		puzzle.Source = "Nowhere"
+		puzzle.Difficulty = "Hard"
+
+		read := roundTrip(t, FormatSDK, []Puzzle{puzzle})
+		checkGivens(t, read[0], puzzle)
+
+		got := read[0]
+
+		if got.Name != puzzle.Name || got.Author != puzzle.Author || got.Comment != puzzle.Comment || got.Source != puzzle.Source || got.Difficulty != puzzle.Difficulty {
+			t.Errorf("metadata %+v, want %+v", got, puzzle)
+		}
+	}
+}
+
+func TestRoundTripSS(t *testing.T) {
+	for _, shape := range sudoku.Shapes() {
+		puzzle := newPuzzle(shape)
+
+		for idx := range puzzle.Givens {
+			if idx%4 == 1 {
+				puzzle.Givens[idx] = byte(idx%shape.Size() + 1)
+			}
+		}
+
+		read := roundTrip(t, FormatSS, []Puzzle{puzzle})
+		checkGivens(t, read[0], puzzle)
+	}
+}
+
+// TestRoundTripPM checks the pencil mark grid keeps givens, entries and
+// notes apart, including notes of one candidate and none
+func TestRoundTripPM(t *testing.T) {
+	shape := sudoku.Classic
+	puzzle := newPuzzle(shape)
+	puzzle.HasNotes = true
+	puzzle.Givens = parseGivens(t, shape, classicGivens)
+
+	empty := []int{}
+
+	for idx, value := range puzzle.Givens {
+		if value == 0 {
+			empty = append(empty, idx)
+		}
+	}
+
+	puzzle.Entries[empty[0]] = 4
+	puzzle.Notes[empty[1]] = sudoku.CandidatesOf(7)
+	puzzle.Notes[empty[2]] = sudoku.CandidatesOf(1, 2, 9)
+	puzzle.Notes[empty[3]] = shape.AllCandidates()
+
+	read := roundTrip(t, FormatPM, []Puzzle{puzzle})
+	got := read[0]
+
+	checkGivens(t, got, puzzle)
+
+	if !got.HasNotes {
+		t.Errorf("notes were not kept")
+	}
+
+	if !slices.Equal(got.Entries, puzzle.Entries) {
+		t.Errorf("entries %s, want %s", got.Entries, puzzle.Entries)
+	}
+
+	if !slices.Equal(got.Notes, puzzle.Notes) {
+		t.Errorf("notes %v, want %v", got.Notes, puzzle.Notes)
+	}
+}
+
+// TestWritePMCandidates checks a puzzle without notes is written with the
+// candidates its givens leave, which read back as notes
+func TestWritePMCandidates(t *testing.T) {
+	shape := sudoku.Classic
+	puzzle := newPuzzle(shape)
+	puzzle.Givens = parseGivens(t, shape, classicGivens)
+
+	board, err := sudoku.FromGrid(shape, puzzle.Givens)
+
+	if err != nil {
+		t.Fatalf("building board: %s", err)
+	}
+
+	read := roundTrip(t, FormatPM, []Puzzle{puzzle})
+	checkGivens(t, read[0], puzzle)
+
+	for idx, value := range puzzle.Givens {
+		if value == 0 && read[0].Notes[idx] != board.Candidates(idx) {
+			t.Errorf("cell %d has notes %v, want %v", idx, read[0].Notes[idx], board.Candidates(idx))
+		}
+	}
+}
+
+func TestReadPMErrors(t *testing.T) {
+	row := "| 1 2 3 | 4 5 6 | 7 8 9 |\n"
+
+	for _, cell := range []string{"+", "+12", "1+", "x"} {
+		text := "| " + cell + " 2 3 | 4 5 6 | 7 8 9 |\n"
+
+		for range 8 {
+			text += row
+		}
+
+		if _, err := readPM(text); err == nil {
+			t.Errorf("cell %q was accepted", cell)
+		}
+	}
+}
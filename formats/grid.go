@@ -0,0 +1,277 @@
+package formats
+
+import (
+	"strings"
+
+	"main/sudoku"
+)
+
+// readSDK reads a SadMan grid with as many rows as cells in a row, taking
+// metadata from #-prefixed lines. A [Regions] section holds the region map
+// of a jigsaw puzzle laid out like the grid, and other sections are skipped.
+func readSDK(text string) ([]Puzzle, error) {
+	puzzle := Puzzle{}
+	metadata := Puzzle{}
+	size := 0
+	row := 0
+	section := "[puzzle]"
+	regions := []string{}
+	regionsLine := 0
+	lastLine := 0
+
+	for line_num, line := range splitLines(text) {
+		trimmed := strings.TrimRight(line, " \t")
+		lastLine = line_num + 1
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			section = strings.ToLower(trimmed)
+
+			if section == "[regions]" {
+				regionsLine = line_num + 1
+			}
+
+			continue
+		case section == "[regions]":
+			regions = append(regions, strings.TrimSpace(trimmed))
+			continue
+		case section != "[puzzle]":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			readSDKMetadata(&metadata, trimmed)
+			continue
+		}
+
+		// The first row decides the size of the grid
+		if row == 0 {
+			shape, err := sudoku.ShapeForSize(len(trimmed))
+
+			if err != nil {
+				return nil, parseErrorf(line_num+1, 1, "%s", err)
+			}
+
+			puzzle = newPuzzle(shape)
+			size = shape.Size()
+		}
+
+		if row == size {
+			return nil, parseErrorf(line_num+1, 1, "unexpected row after %d rows", size)
+		}
+
+		if len(trimmed) != size {
+			return nil, parseErrorf(line_num+1, min(len(trimmed), size)+1, "expected %d cells in row, got %d", size, len(trimmed))
+		}
+
+		for col := range size {
+			value, ok := cellValue(puzzle.Shape, trimmed[col])
+
+			if !ok {
+				return nil, parseErrorf(line_num+1, col+1, "invalid cell character %q", trimmed[col])
+			}
+
+			puzzle.Givens[puzzle.Shape.Index(row, col)] = value
+		}
+
+		row++
+	}
+
+	if row == 0 || row != size {
+		return nil, parseErrorf(lastLine, 1, "expected %d rows, got %d", max(size, sudoku.Classic.Size()), row)
+	}
+
+	if regionsLine > 0 {
+		for _, line := range regions {
+			if len(line) != size || len(regions) != size {
+				return nil, parseErrorf(regionsLine, 1, "expected %d rows of %d regions", size, size)
+			}
+		}
+
+		jigsaw, err := puzzle.Shape.ParseRegions(strings.Join(regions, ""))
+
+		if err != nil {
+			return nil, parseErrorf(regionsLine, 1, "%s", err)
+		}
+
+		puzzle.Shape = jigsaw
+	}
+
+	puzzle.Name = metadata.Name
+	puzzle.Author = metadata.Author
+	puzzle.Comment = metadata.Comment
+	puzzle.Source = metadata.Source
+	puzzle.Difficulty = metadata.Difficulty
+
+	return []Puzzle{puzzle}, nil
+}
+
+func readSDKMetadata(puzzle *Puzzle, line string) {
+	if len(line) < 2 {
+		return
+	}
+
+	value := strings.TrimSpace(line[2:])
+
+	switch line[1] {
+	case 'A':
+		puzzle.Author = value
+	case 'D':
+		puzzle.Name = value
+	case 'C':
+		puzzle.Comment = value
+	case 'S':
+		puzzle.Source = value
+	case 'L':
+		puzzle.Difficulty = value
+	}
+}
+
+func writeSDK(puzzle Puzzle) string {
+	var sb strings.Builder
+
+	for _, field := range []struct {
+		key   byte
+		value string
+	}{
+		{'D', puzzle.Name},
+		{'A', puzzle.Author},
+		{'C', puzzle.Comment},
+		{'S', puzzle.Source},
+		{'L', puzzle.Difficulty},
+	} {
+		if field.value != "" {
+			sb.WriteString("#" + string(field.key) + " " + field.value + "\n")
+		}
+	}
+
+	shape := puzzle.Shape
+
+	for row := range shape.Size() {
+		for col := range shape.Size() {
+			sb.WriteByte(sudoku.Symbol(puzzle.Givens[shape.Index(row, col)]))
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	if shape.Jigsaw() {
+		sb.WriteString("[Regions]\n")
+
+		for row := range shape.Size() {
+			sb.WriteString(shape.Regions[row*shape.Size():(row+1)*shape.Size()] + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// readSS reads a Simple Sudoku grid, skipping the border characters between
+// boxes and the separator lines between bands
+func readSS(text string) ([]Puzzle, error) {
+	puzzle := Puzzle{}
+	size := 0
+	row := 0
+	lastLine := 0
+
+	for line_num, line := range splitLines(text) {
+		trimmed := strings.TrimSpace(line)
+		lastLine = line_num + 1
+
+		if trimmed == "" || strings.Trim(trimmed, "-+*|") == "" {
+			continue
+		}
+
+		// The first row decides the size of the grid
+		if row == 0 {
+			shape, err := sudoku.ShapeForSize(len(ssCells.Replace(line)))
+
+			if err != nil {
+				return nil, parseErrorf(line_num+1, 1, "%s", err)
+			}
+
+			puzzle = newPuzzle(shape)
+			size = shape.Size()
+		}
+
+		if row == size {
+			return nil, parseErrorf(line_num+1, 1, "unexpected row after %d rows", size)
+		}
+
+		col := 0
+
+		for i := 0; i < len(line); i++ {
+			ch := line[i]
+
+			if ch == '|' || ch == ' ' || ch == '\t' {
+				continue
+			}
+
+			value, ok := cellValue(puzzle.Shape, ch)
+
+			if ch == 'X' || ch == 'x' {
+				value, ok = 0, true
+			}
+
+			if !ok {
+				return nil, parseErrorf(line_num+1, i+1, "invalid cell character %q", ch)
+			}
+
+			if col == size {
+				return nil, parseErrorf(line_num+1, i+1, "more than %d cells in row", size)
+			}
+
+			puzzle.Givens[puzzle.Shape.Index(row, col)] = value
+			col++
+		}
+
+		if col != size {
+			return nil, parseErrorf(line_num+1, len(line)+1, "expected %d cells in row, got %d", size, col)
+		}
+
+		row++
+	}
+
+	if row == 0 || row != size {
+		return nil, parseErrorf(lastLine, 1, "expected %d rows, got %d", max(size, sudoku.Classic.Size()), row)
+	}
+
+	return []Puzzle{puzzle}, nil
+}
+
+// ssCells strips the borders from a Simple Sudoku row, leaving one character per cell
+var ssCells = strings.NewReplacer("|", "", " ", "", "\t", "")
+
+func writeSS(puzzle Puzzle) string {
+	var sb strings.Builder
+
+	shape := puzzle.Shape
+	stacks := shape.Size() / shape.BoxWidth
+
+	border := "*" + strings.Repeat("-", shape.Size()+stacks-1) + "*\n"
+	separator := "|" + strings.Repeat(strings.Repeat("-", shape.BoxWidth)+"+", stacks-1) + strings.Repeat("-", shape.BoxWidth) + "|\n"
+
+	sb.WriteString(border)
+
+	for row := range shape.Size() {
+		if row > 0 && row%shape.BoxHeight == 0 {
+			sb.WriteString(separator)
+		}
+
+		sb.WriteByte('|')
+
+		for col := range shape.Size() {
+			sb.WriteByte(sudoku.Symbol(puzzle.Givens[shape.Index(row, col)]))
+
+			if col%shape.BoxWidth == shape.BoxWidth-1 {
+				sb.WriteByte('|')
+			}
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(border)
+
+	return sb.String()
+}
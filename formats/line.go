@@ -0,0 +1,115 @@
+package formats
+
+import (
+	"strings"
+
+	"main/sudoku"
+)
+
+// readSDM reads one puzzle per line, ignoring blank lines and anything after
+// the cells such as ratings or comments. The number of cells decides the
+// size of each grid. A jigsaw puzzle follows its cells with a region map of
+// as many characters, where cells sharing a character share a region. The
+// grids of a multi-grid layout are written one after another, each giving
+// the cells it shares with the others again.
+func readSDM(text string) ([]Puzzle, error) {
+	puzzles := []Puzzle{}
+
+	for line_num, line := range splitLines(text) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		start := len(line) - len(strings.TrimLeft(line, " \t"))
+		end := start
+
+		for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+			end++
+		}
+
+		shape, err := sudoku.ShapeForCells(end - start)
+
+		if err != nil {
+			shape, err = sudoku.MultiShapeForCells(end - start)
+		}
+
+		if err != nil {
+			return nil, parseErrorf(line_num+1, end+1, "no grid or layout of grids has %d cells", end-start)
+		}
+
+		puzzle := newPuzzle(shape)
+		read := make([]bool, shape.NumCells())
+
+		for i, idx := range gridOrder(shape) {
+			col := start + i
+			value, ok := cellValue(shape, line[col])
+
+			if !ok {
+				return nil, parseErrorf(line_num+1, col+1, "invalid cell character %q", line[col])
+			}
+
+			// A cell shared between grids must be written alike in each
+			if read[idx] && puzzle.Givens[idx] != value {
+				return nil, parseErrorf(line_num+1, col+1, "cell %s is %q here but %q in an earlier grid", shape.CellName(idx), line[col], sudoku.Symbol(puzzle.Givens[idx]))
+			}
+
+			puzzle.Givens[idx] = value
+			read[idx] = true
+		}
+
+		start = end + len(line[end:]) - len(strings.TrimLeft(line[end:], " \t"))
+		end = start
+
+		for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+			end++
+		}
+
+		if !shape.Multi() && end-start == shape.NumCells() {
+			jigsaw, err := shape.ParseRegions(line[start:end])
+
+			if err != nil {
+				return nil, parseErrorf(line_num+1, start+1, "%s", err)
+			}
+
+			puzzle.Shape = jigsaw
+		}
+
+		puzzles = append(puzzles, puzzle)
+	}
+
+	if len(puzzles) == 0 {
+		return nil, parseErrorf(1, 1, "no puzzles found")
+	}
+
+	return puzzles, nil
+}
+
+func writeSDM(puzzles []Puzzle) string {
+	var sb strings.Builder
+
+	for _, puzzle := range puzzles {
+		for _, idx := range gridOrder(puzzle.Shape) {
+			sb.WriteByte(sudoku.Symbol(puzzle.Givens[idx]))
+		}
+
+		if puzzle.Shape.Jigsaw() {
+			sb.WriteString(" " + puzzle.Shape.Regions)
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// gridOrder lists the cells in the order the line format writes them, grid
+// by grid, so a cell shared by several grids appears once for each
+func gridOrder(shape sudoku.Shape) []int {
+	order := []int{}
+
+	for grid := range shape.NumGrids() {
+		order = append(order, shape.GridCells(grid)...)
+	}
+
+	return order
+}
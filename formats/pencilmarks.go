@@ -0,0 +1,227 @@
+package formats
+
+import (
+	"strings"
+
+	"main/sudoku"
+)
+
+// PM_ENTRY marks a digit the player placed, as opposed to a given, and
+// PM_NOTES ends a run of candidates, so a cell with one candidate or none
+// is not mistaken for a given
+const (
+	PM_ENTRY = '+'
+	PM_NOTES = '.'
+)
+
+// readPM reads a pencil mark grid where each cell is a run of digits. A
+// single digit is a given, one after PM_ENTRY a placed value, and several
+// digits, or any ending in PM_NOTES, are the cell's candidates.
+func readPM(text string) ([]Puzzle, error) {
+	puzzle := Puzzle{}
+	size := 0
+	row := 0
+	lastLine := 0
+
+	for line_num, line := range splitLines(text) {
+		trimmed := strings.TrimSpace(line)
+		lastLine = line_num + 1
+
+		// Skip blank lines and the borders drawn between bands
+		if trimmed == "" || strings.Trim(trimmed, ".:'-+*|") == "" {
+			continue
+		}
+
+		// The first row decides the size of the grid
+		if row == 0 {
+			shape, err := sudoku.ShapeForSize(len(strings.Fields(strings.ReplaceAll(line, "|", " "))))
+
+			if err != nil {
+				return nil, parseErrorf(line_num+1, 1, "%s", err)
+			}
+
+			puzzle = newPuzzle(shape)
+			puzzle.HasNotes = true
+			size = shape.Size()
+		}
+
+		if row == size {
+			return nil, parseErrorf(line_num+1, 1, "unexpected row after %d rows", size)
+		}
+
+		col := 0
+
+		for i := 0; i < len(line); {
+			ch := line[i]
+
+			if ch == '|' || ch == ' ' || ch == '\t' {
+				i++
+				continue
+			}
+
+			start := i
+
+			for i < len(line) && line[i] != '|' && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+
+			if col == size {
+				return nil, parseErrorf(line_num+1, start+1, "more than %d cells in row", size)
+			}
+
+			token := line[start:i]
+			entry := token[0] == PM_ENTRY
+			notes := !entry && token[len(token)-1] == PM_NOTES
+
+			if entry {
+				start++
+			}
+
+			if notes {
+				i--
+			}
+
+			candidates := sudoku.Candidates(0)
+
+			for j := start; j < i; j++ {
+				value, ok := cellValue(puzzle.Shape, line[j])
+
+				if !ok || value == 0 {
+					return nil, parseErrorf(line_num+1, j+1, "invalid candidate %q", line[j])
+				}
+
+				candidates = candidates.Add(value)
+			}
+
+			if notes {
+				i++
+			}
+
+			idx := puzzle.Shape.Index(row, col)
+			value, single := candidates.Single()
+
+			switch {
+			case entry && !single:
+				return nil, parseErrorf(line_num+1, start, "expected 1 digit after %q, got %d", PM_ENTRY, candidates.Count())
+			case entry:
+				puzzle.Entries[idx] = value
+			case single && !notes:
+				puzzle.Givens[idx] = value
+			default:
+				puzzle.Notes[idx] = candidates
+			}
+
+			col++
+		}
+
+		if col != size {
+			return nil, parseErrorf(line_num+1, len(line)+1, "expected %d cells in row, got %d", size, col)
+		}
+
+		row++
+	}
+
+	if row == 0 || row != size {
+		return nil, parseErrorf(lastLine, 1, "expected %d rows, got %d", max(size, sudoku.Classic.Size()), row)
+	}
+
+	return []Puzzle{puzzle}, nil
+}
+
+// writePM renders the puzzle as a pencil mark grid, filling in candidates
+// from the givens when the puzzle carries no notes of its own. Notes that
+// could be read back as a given are ended with PM_NOTES.
+func writePM(puzzle Puzzle) string {
+	shape := puzzle.Shape
+	stacks := shape.Size() / shape.BoxWidth
+	tokens := make([]string, shape.NumCells())
+	widths := make([]int, shape.Size())
+
+	board := sudoku.NewBoard(shape)
+
+	if !puzzle.HasNotes {
+		if b, err := sudoku.FromGrid(shape, puzzle.Givens); err == nil {
+			board = b
+		}
+	}
+
+	for idx := range tokens {
+		var sb strings.Builder
+
+		switch {
+		case puzzle.Givens[idx] != 0:
+			sb.WriteByte(sudoku.Symbol(puzzle.Givens[idx]))
+		case puzzle.Entries != nil && puzzle.Entries[idx] != 0:
+			sb.WriteByte(PM_ENTRY)
+			sb.WriteByte(sudoku.Symbol(puzzle.Entries[idx]))
+		default:
+			notes := board.Candidates(idx)
+
+			if puzzle.HasNotes {
+				notes = puzzle.Notes[idx]
+			}
+
+			for _, digit := range notes.Digits() {
+				sb.WriteByte(sudoku.Symbol(digit))
+			}
+
+			if notes.Count() < 2 {
+				sb.WriteByte(PM_NOTES)
+			}
+		}
+
+		tokens[idx] = sb.String()
+		widths[shape.ColOf(idx)] = max(widths[shape.ColOf(idx)], len(tokens[idx]))
+	}
+
+	border := func(corner string, middle string) string {
+		var sb strings.Builder
+
+		sb.WriteString(corner)
+
+		for stack := range stacks {
+			span := 1
+
+			for col := stack * shape.BoxWidth; col < (stack+1)*shape.BoxWidth; col++ {
+				span += widths[col] + 2
+			}
+
+			sb.WriteString(strings.Repeat("-", span))
+
+			if stack < stacks-1 {
+				sb.WriteString(middle)
+			}
+		}
+
+		sb.WriteString(corner + "\n")
+
+		return sb.String()
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(border(".", "."))
+
+	for row := range shape.Size() {
+		if row > 0 && row%shape.BoxHeight == 0 {
+			sb.WriteString(border(":", "+"))
+		}
+
+		sb.WriteByte('|')
+
+		for col := range shape.Size() {
+			token := tokens[shape.Index(row, col)]
+			sb.WriteString(" " + token + strings.Repeat(" ", widths[col]-len(token)+1))
+
+			if col%shape.BoxWidth == shape.BoxWidth-1 {
+				sb.WriteString(" |")
+			}
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(border("'", "'"))
+
+	return sb.String()
+}
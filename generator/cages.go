@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"math/rand"
+	"slices"
+
+	"main/sudoku"
+)
+
+const (
+	MIN_CAGE_SIZE = 2
+	MAX_CAGE_SIZE = 5
+)
+
+// makeCages covers a solved grid in random connected cages whose digits do
+// not repeat, summing each cage from the solution
+func makeCages(rng *rand.Rand, shape sudoku.Shape, solution sudoku.Grid) []sudoku.Cage {
+	cageOf := make([]int, len(solution))
+
+	for idx := range cageOf {
+		cageOf[idx] = -1
+	}
+
+	cells := [][]int{}
+
+	for _, start := range rng.Perm(len(solution)) {
+		if cageOf[start] >= 0 {
+			continue
+		}
+
+		cage := len(cells)
+		size := MIN_CAGE_SIZE + rng.Intn(MAX_CAGE_SIZE-MIN_CAGE_SIZE+1)
+		members := []int{start}
+		used := sudoku.CandidatesOf(solution[start])
+		cageOf[start] = cage
+
+		for len(members) < size {
+			open := []int{}
+
+			for _, idx := range members {
+				for _, next := range neighbors(shape, idx) {
+					if cageOf[next] < 0 && !used.Has(solution[next]) && !slices.Contains(open, next) {
+						open = append(open, next)
+					}
+				}
+			}
+
+			if len(open) == 0 {
+				break
+			}
+
+			next := open[rng.Intn(len(open))]
+			members = append(members, next)
+			used = used.Add(solution[next])
+			cageOf[next] = cage
+		}
+
+		cells = append(cells, members)
+	}
+
+	// Fold lone cells into a neighboring cage where the digit still fits
+	for i, members := range cells {
+		if len(members) != 1 {
+			continue
+		}
+
+		idx := members[0]
+
+		for _, next := range neighbors(shape, idx) {
+			target := cageOf[next]
+
+			if target == i || len(cells[target]) >= MAX_CAGE_SIZE || !fits(cells[target], solution, solution[idx]) {
+				continue
+			}
+
+			cells[target] = append(cells[target], idx)
+			cells[i] = nil
+			cageOf[idx] = target
+
+			break
+		}
+	}
+
+	cages := []sudoku.Cage{}
+
+	for _, members := range cells {
+		if len(members) == 0 {
+			continue
+		}
+
+		slices.Sort(members)
+		sum := 0
+
+		for _, idx := range members {
+			sum += int(solution[idx])
+		}
+
+		cages = append(cages, sudoku.Cage{Cells: members, Sum: sum})
+	}
+
+	return cages
+}
+
+func fits(members []int, solution sudoku.Grid, digit byte) bool {
+	for _, idx := range members {
+		if solution[idx] == digit {
+			return false
+		}
+	}
+
+	return true
+}
+
+// neighbors lists the cells sharing an edge with a cell
+func neighbors(shape sudoku.Shape, idx int) []int {
+	row, col := shape.RowCol(idx)
+	cells := []int{}
+
+	for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		r, c := row+d[0], col+d[1]
+
+		if r >= 0 && r < shape.Height() && c >= 0 && c < shape.Width() && shape.Index(r, c) >= 0 {
+			cells = append(cells, shape.Index(r, c))
+		}
+	}
+
+	return cells
+}
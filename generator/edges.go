@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"math/rand"
+
+	"main/sudoku"
+)
+
+const (
+	// EDGE_CHANCE is the chance a side that could take a marker is given
+	// one, unless the constraint is negative and every such side needs one
+	EDGE_CHANCE = 0.5
+
+	// NEGATIVE_EDGE_ATTEMPTS bounds the attempts at a negative puzzle, since
+	// markers on every side leave so little to find that Hard grades are
+	// rarely reached and more attempts only delay settling for less
+	NEGATIVE_EDGE_ATTEMPTS = 10
+)
+
+// makeEdges marks the sides between cells of a solved grid with the markers
+// of a kind that their digits follow, picking at random where a side could
+// take more than one
+func makeEdges(rng *rand.Rand, shape sudoku.Shape, solution sudoku.Grid, kind sudoku.EdgeConstraint) sudoku.EdgeConstraint {
+	edges := []sudoku.Edge{}
+
+	for idx := range solution {
+		row, col := shape.RowCol(idx)
+
+		// Each side is visited from the cell above or left of it
+		for _, d := range [2][2]int{{0, 1}, {1, 0}} {
+			r, c := row+d[0], col+d[1]
+
+			if r >= shape.Height() || c >= shape.Width() || shape.Index(r, c) < 0 {
+				continue
+			}
+
+			cells := [2]int{idx, shape.Index(r, c)}
+
+			// Greater than signs point from the larger digit
+			if solution[cells[0]] < solution[cells[1]] {
+				cells[0], cells[1] = cells[1], cells[0]
+			}
+
+			markers := []sudoku.Marker{}
+
+			for _, marker := range kind.Markers() {
+				if marker.Allows(solution[cells[0]], solution[cells[1]]) {
+					markers = append(markers, marker)
+				}
+			}
+
+			if len(markers) == 0 || !kind.Negative() && rng.Float64() >= EDGE_CHANCE {
+				continue
+			}
+
+			edges = append(edges, sudoku.Edge{Cells: cells, Marker: markers[rng.Intn(len(markers))]})
+		}
+	}
+
+	return kind.WithEdges(edges, kind.Negative())
+}
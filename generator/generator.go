@@ -0,0 +1,347 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"main/logic"
+	"main/solver"
+	"main/sudoku"
+)
+
+// FILL_BUDGET bounds the cells tried while filling a grid, since a grid with
+// constraints can trap the search long before it backtracks out
+const FILL_BUDGET = 1 << 20
+
+// ErrDeadline is returned when generation passes Options.Deadline
+var ErrDeadline = errors.New("puzzle generation ran out of time")
+
+type Options struct {
+	Shape      sudoku.Shape
+	Seed       int64
+	Difficulty sudoku.Difficulty
+	MinClues   int
+	MaxClues   int
+	Symmetry   Symmetry
+
+	// Killer covers the grid in cages, which usually lets most givens go
+	Killer bool
+
+	// Jigsaw jumbles the boxes of the shape into irregular regions, giving
+	// every attempt a new layout
+	Jigsaw bool
+
+	// Constraints are variant rules the solution and the solving path follow
+	Constraints sudoku.Constraints
+
+	// Lines is a kind of line constraint, such as sudoku.Thermo{}, whose
+	// lines are drawn afresh over each solution
+	Lines sudoku.LineConstraint
+
+	// Edges is a kind of edge constraint, such as sudoku.Kropki{}, whose
+	// markers are put afresh on each solution. Every side that could take
+	// a marker gets one when the kind is negative.
+	Edges sudoku.EdgeConstraint
+
+	// Attempts bounds how many candidate puzzles are tried before giving up
+	Attempts int
+
+	// Deadline, when set, stops generation with ErrDeadline once passed,
+	// since some variants take minutes to reach a grade
+	Deadline time.Time
+}
+
+type Puzzle struct {
+	Shape      sudoku.Shape
+	Givens     sudoku.Grid
+	Solution   sudoku.Grid
+	Seed       int64
+	Difficulty sudoku.Difficulty
+	Symmetry   Symmetry
+	Cages      []sudoku.Cage
+
+	Constraints sudoku.Constraints
+}
+
+func DefaultOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64) Options {
+	opts := Options{
+		Shape:      shape,
+		Seed:       seed,
+		Difficulty: difficulty,
+		MaxClues:   shape.NumCells(),
+		Symmetry:   SymmetryRotational,
+		Attempts:   100,
+	}
+
+	// No classic puzzle with fewer than 17 clues has a unique solution
+	if shape == sudoku.Classic {
+		opts.MinClues = 17
+	}
+
+	return opts
+}
+
+// KillerOptions are the default options for a Killer puzzle, which may end
+// up with no givens at all
+func KillerOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64) Options {
+	opts := DefaultOptions(shape, difficulty, seed)
+	opts.MinClues = 0
+	opts.Killer = true
+
+	return opts
+}
+
+// JigsawOptions are the default options for a puzzle with irregular regions
+// in place of boxes
+func JigsawOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64) Options {
+	opts := DefaultOptions(shape, difficulty, seed)
+	opts.MinClues = 0
+	opts.Jigsaw = true
+
+	return opts
+}
+
+// VariantOptions are the default options for a puzzle with constraints,
+// which can stay unique with fewer givens than a classic one
+func VariantOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64, constraints sudoku.Constraints) Options {
+	opts := DefaultOptions(shape, difficulty, seed)
+	opts.MinClues = 0
+	opts.Constraints = constraints
+
+	return opts
+}
+
+// LineOptions are the default options for a puzzle with lines of a kind
+// drawn over it
+func LineOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64, kind sudoku.LineConstraint) Options {
+	opts := DefaultOptions(shape, difficulty, seed)
+	opts.MinClues = 0
+	opts.Lines = kind
+
+	return opts
+}
+
+// EdgeOptions are the default options for a puzzle with markers of a kind
+// between its cells
+func EdgeOptions(shape sudoku.Shape, difficulty sudoku.Difficulty, seed int64, kind sudoku.EdgeConstraint) Options {
+	opts := DefaultOptions(shape, difficulty, seed)
+	opts.MinClues = 0
+	opts.Edges = kind
+
+	if kind.Negative() {
+		opts.Attempts = NEGATIVE_EDGE_ATTEMPTS
+	}
+
+	return opts
+}
+
+// Generate builds a puzzle with a unique solution from the options. The same
+// options always produce the same puzzle, unless the deadline cuts it short.
+func Generate(opts Options) (Puzzle, error) {
+	err := opts.Shape.Validate()
+
+	if err != nil {
+		return Puzzle{}, err
+	}
+
+	if opts.Killer && opts.Shape.Size() > sudoku.MaxCageDigits {
+		return Puzzle{}, fmt.Errorf("killer puzzles need a grid of at most %d digits", sudoku.MaxCageDigits)
+	}
+
+	if opts.Shape.Multi() && opts.Killer {
+		return Puzzle{}, fmt.Errorf("the %s layout cannot have cages", opts.Shape)
+	}
+
+	if opts.Shape.Multi() && opts.Jigsaw {
+		return Puzzle{}, fmt.Errorf("the %s layout cannot have jigsaw regions", opts.Shape)
+	}
+
+	constraints := opts.Constraints
+
+	if opts.Lines != nil {
+		constraints = append(slices.Clone(constraints), opts.Lines)
+	}
+
+	if opts.Edges != nil {
+		constraints = append(slices.Clone(constraints), opts.Edges)
+	}
+
+	err = constraints.Validate(opts.Shape)
+
+	if err != nil {
+		return Puzzle{}, err
+	}
+
+	if opts.MinClues < 0 || opts.MaxClues > opts.Shape.NumCells() || opts.MinClues > opts.MaxClues {
+		return Puzzle{}, fmt.Errorf("invalid clue range: %d to %d", opts.MinClues, opts.MaxClues)
+	}
+
+	if opts.Attempts < 1 {
+		opts.Attempts = 1
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	for range opts.Attempts {
+		if opts.expired() {
+			return Puzzle{}, ErrDeadline
+		}
+
+		shape := opts.Shape
+
+		if opts.Jigsaw {
+			shape, err = makeRegions(rng, opts.Shape)
+
+			if err != nil {
+				return Puzzle{}, err
+			}
+		}
+
+		solution, ok := fill(rng, shape, opts.Constraints)
+
+		if !ok {
+			continue
+		}
+
+		variant := sudoku.Variant{Constraints: opts.Constraints}
+
+		if opts.Lines != nil {
+			variant.Constraints = append(slices.Clone(variant.Constraints), makeLines(rng, shape, solution, opts.Lines))
+		}
+
+		if opts.Edges != nil {
+			variant.Constraints = append(slices.Clone(variant.Constraints), makeEdges(rng, shape, solution, opts.Edges))
+		}
+
+		if opts.Killer {
+			variant.Cages = makeCages(rng, shape, solution)
+		}
+
+		givens, ok := dig(rng, shape, solution, variant, opts)
+
+		if !ok {
+			return Puzzle{}, ErrDeadline
+		}
+
+		clues := givens.Clues()
+
+		if clues < opts.MinClues || clues > opts.MaxClues {
+			continue
+		}
+
+		if logic.GradeVariant(shape, givens, variant) != opts.Difficulty {
+			continue
+		}
+
+		return Puzzle{
+			Shape:      shape,
+			Givens:     givens,
+			Solution:   solution,
+			Seed:       opts.Seed,
+			Difficulty: opts.Difficulty,
+			Symmetry:   opts.Symmetry,
+			Cages:      variant.Cages,
+
+			Constraints: variant.Constraints,
+		}, nil
+	}
+
+	return Puzzle{}, fmt.Errorf("no %s %s puzzle found for seed %d after %d attempts", opts.Difficulty, opts.Shape, opts.Seed, opts.Attempts)
+}
+
+// fill builds a random complete grid following the constraints, reporting
+// false if the search gave up first
+func fill(rng *rand.Rand, shape sudoku.Shape, constraints sudoku.Constraints) (sudoku.Grid, bool) {
+	grid := sudoku.NewGrid(shape)
+	peers := make([][]int, len(grid))
+
+	for idx := range grid {
+		peers[idx] = shape.Peers(idx)
+	}
+
+	// Cells sharing one of the extra houses may not repeat a digit either
+	for _, house := range constraints.Houses(shape)[shape.NumHouses():] {
+		for _, idx := range house {
+			peers[idx] = append(slices.Clip(peers[idx]), house...)
+		}
+	}
+
+	budget := FILL_BUDGET
+
+	return grid, fillFrom(rng, shape, constraints, peers, grid, 0, &budget)
+}
+
+func fillFrom(rng *rand.Rand, shape sudoku.Shape, constraints sudoku.Constraints, peers [][]int, grid sudoku.Grid, idx int, budget *int) bool {
+	if idx == len(grid) {
+		return true
+	}
+
+	if *budget--; *budget < 0 {
+		return false
+	}
+
+	used := constraints.Excluded(shape, grid, idx)
+
+	for _, peer := range peers[idx] {
+		used = used.Add(grid[peer])
+	}
+
+	digits := (shape.AllCandidates() &^ used).Digits()
+	rng.Shuffle(len(digits), func(i, j int) { digits[i], digits[j] = digits[j], digits[i] })
+
+	for _, digit := range digits {
+		grid[idx] = digit
+
+		if fillFrom(rng, shape, constraints, peers, grid, idx+1, budget) {
+			return true
+		}
+	}
+
+	grid[idx] = 0
+
+	return false
+}
+
+// expired reports whether the deadline, if any, has passed
+func (opts Options) expired() bool {
+	return !opts.Deadline.IsZero() && time.Now().After(opts.Deadline)
+}
+
+// dig removes symmetric groups of clues from the solution in random order,
+// keeping each removal only if the puzzle stays unique and no harder than
+// requested. It reports false if the deadline passed first.
+func dig(rng *rand.Rand, shape sudoku.Shape, solution sudoku.Grid, variant sudoku.Variant, opts Options) (sudoku.Grid, bool) {
+	givens := solution.Clone()
+	clues := len(givens)
+
+	orbits := opts.Symmetry.orbits(shape)
+	rng.Shuffle(len(orbits), func(i, j int) { orbits[i], orbits[j] = orbits[j], orbits[i] })
+
+	for _, orbit := range orbits {
+		if opts.expired() {
+			return nil, false
+		}
+
+		if clues-len(orbit) < opts.MinClues {
+			continue
+		}
+
+		for _, idx := range orbit {
+			givens[idx] = 0
+		}
+
+		if solver.UniqueVariant(shape, givens, variant) && logic.GradeVariant(shape, givens, variant) <= opts.Difficulty {
+			clues -= len(orbit)
+			continue
+		}
+
+		for _, idx := range orbit {
+			givens[idx] = solution[idx]
+		}
+	}
+
+	return givens, true
+}
@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"main/logic"
+	"main/solver"
+	"main/sudoku"
+)
+
+// TEST_TIMEOUT bounds each generation, past which the test fails rather
+// than hanging
+const TEST_TIMEOUT = time.Minute
+
+// generate runs Generate with a deadline, failing the test if it errors
+func generate(t *testing.T, opts Options) Puzzle {
+	t.Helper()
+
+	opts.Deadline = time.Now().Add(TEST_TIMEOUT)
+
+	puzzle, err := Generate(opts)
+
+	if err != nil {
+		t.Fatalf("generating %s %s puzzle: %s", opts.Difficulty, opts.Shape, err)
+	}
+
+	return puzzle
+}
+
+// checkPuzzle checks a puzzle has one solution, the one it was generated
+// from, and takes exactly the requested grade to solve
+func checkPuzzle(t *testing.T, puzzle Puzzle, difficulty sudoku.Difficulty) {
+	t.Helper()
+
+	variant := sudoku.Variant{Cages: puzzle.Cages, Constraints: puzzle.Constraints}
+
+	solution, err := solver.VariantSolution(puzzle.Shape, puzzle.Givens, variant)
+
+	if err != nil {
+		t.Fatalf("%s: %s", puzzle.Givens, err)
+	}
+
+	if solution.String() != puzzle.Solution.String() {
+		t.Errorf("solution %s, generated from %s", solution, puzzle.Solution)
+	}
+
+	for idx, value := range puzzle.Givens {
+		if value != 0 && value != puzzle.Solution[idx] {
+			t.Errorf("given %d in cell %d, solution has %d", value, idx, puzzle.Solution[idx])
+		}
+	}
+
+	if puzzle.Difficulty != difficulty {
+		t.Errorf("puzzle reports %s, requested %s", puzzle.Difficulty, difficulty)
+	}
+
+	if graded := logic.GradeVariant(puzzle.Shape, puzzle.Givens, variant); graded != difficulty {
+		t.Errorf("puzzle grades %s, requested %s", graded, difficulty)
+	}
+}
+
+func TestGenerateDifficulties(t *testing.T) {
+	for _, difficulty := range sudoku.Difficulties() {
+		t.Run(difficulty.String(), func(t *testing.T) {
+			puzzle := generate(t, DefaultOptions(sudoku.Classic, difficulty, 1))
+			checkPuzzle(t, puzzle, difficulty)
+
+			if clues := puzzle.Givens.Clues(); clues < 17 {
+				t.Errorf("puzzle has %d clues, fewer than any unique classic puzzle", clues)
+			}
+		})
+	}
+}
+
+func TestGenerateShapes(t *testing.T) {
+	for _, shape := range append(sudoku.Shapes()[:3], sudoku.MultiShapes()...) {
+		t.Run(shape.String(), func(t *testing.T) {
+			checkPuzzle(t, generate(t, DefaultOptions(shape, sudoku.Easy, 1)), sudoku.Easy)
+		})
+	}
+}
+
+func TestGenerateVariants(t *testing.T) {
+	variants := map[string]Options{
+		"Killer": KillerOptions(sudoku.Classic, sudoku.Easy, 1),
+		"Jigsaw": JigsawOptions(sudoku.Classic, sudoku.Easy, 1),
+	}
+
+	for _, constraint := range sudoku.AllConstraints() {
+		variants[constraint.Name()] = VariantOptions(sudoku.Classic, sudoku.Easy, 1, sudoku.Constraints{constraint})
+	}
+
+	for _, kind := range sudoku.AllLineConstraints() {
+		variants[kind.Name()] = LineOptions(sudoku.Classic, sudoku.Easy, 1, kind)
+	}
+
+	for _, kind := range sudoku.AllEdgeConstraints() {
+		variants[kind.Name()] = EdgeOptions(sudoku.Classic, sudoku.Easy, 1, kind)
+
+		if kind.Negatable() {
+			variants["Negative "+kind.Name()] = EdgeOptions(sudoku.Classic, sudoku.Easy, 1, kind.WithEdges(nil, true))
+		}
+	}
+
+	for name, opts := range variants {
+		t.Run(name, func(t *testing.T) {
+			checkPuzzle(t, generate(t, opts), sudoku.Easy)
+		})
+	}
+}
+
+// TestGenerateRepeatable checks the same options give the same puzzle
+func TestGenerateRepeatable(t *testing.T) {
+	opts := DefaultOptions(sudoku.Classic, sudoku.Medium, 7)
+
+	first := generate(t, opts)
+	second := generate(t, opts)
+
+	if first.Givens.String() != second.Givens.String() {
+		t.Errorf("seed %d gave %s then %s", opts.Seed, first.Givens, second.Givens)
+	}
+}
+
+func TestGenerateClueRange(t *testing.T) {
+	opts := DefaultOptions(sudoku.Classic, sudoku.Easy, 1)
+	opts.MinClues = 50
+	opts.MaxClues = 40
+
+	if _, err := Generate(opts); err == nil {
+		t.Errorf("generating with more minimum than maximum clues succeeded")
+	}
+}
+
+func TestGenerateDeadline(t *testing.T) {
+	opts := DefaultOptions(sudoku.Classic, sudoku.Expert, 1)
+	opts.Deadline = time.Now().Add(-time.Second)
+
+	if _, err := Generate(opts); err != ErrDeadline {
+		t.Errorf("generating past the deadline returned %v, want ErrDeadline", err)
+	}
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"math/rand"
+	"slices"
+
+	"main/sudoku"
+)
+
+const (
+	MIN_LINE_CELLS = 3
+	MAX_LINE_CELLS = 6
+
+	// LINE_TRIES bounds the random walks tried for each line, since only a
+	// few walks happen to follow a rule such as a palindrome's
+	LINE_TRIES = 500
+)
+
+// makeLines draws lines of a kind over a solved grid, each a random walk of
+// king's moves whose digits from the solution follow the rule. Lines never
+// share a cell or cross each other on a diagonal step.
+func makeLines(rng *rand.Rand, shape sudoku.Shape, solution sudoku.Grid, kind sudoku.LineConstraint) sudoku.LineConstraint {
+	count := shape.Size()/2 + 1
+	longest := min(MAX_LINE_CELLS, shape.Size())
+	lines := [][]int{}
+	used := make([]bool, len(solution))
+
+	for try := 0; len(lines) < count && try < count*LINE_TRIES; try++ {
+		line := walk(rng, shape, lines, used, MIN_LINE_CELLS+rng.Intn(longest-MIN_LINE_CELLS+1))
+
+		if line == nil || !kind.Allows(shape, sudoku.LineDigits(solution, line)) {
+			continue
+		}
+
+		for _, idx := range line {
+			used[idx] = true
+		}
+
+		lines = append(lines, line)
+	}
+
+	return kind.WithLines(lines)
+}
+
+// walk takes a random path of length cells through unused cells, returning
+// nil if it runs into a dead end first
+func walk(rng *rand.Rand, shape sudoku.Shape, lines [][]int, used []bool, length int) []int {
+	line := []int{rng.Intn(len(used))}
+
+	if used[line[0]] {
+		return nil
+	}
+
+	for len(line) < length {
+		last := line[len(line)-1]
+		open := []int{}
+
+		for _, next := range kingNeighbors(shape, last) {
+			if !used[next] && !slices.Contains(line, next) && !crosses(shape, lines, last, next) && !crosses(shape, [][]int{line}, last, next) {
+				open = append(open, next)
+			}
+		}
+
+		if len(open) == 0 {
+			return nil
+		}
+
+		line = append(line, open[rng.Intn(len(open))])
+	}
+
+	return line
+}
+
+func kingNeighbors(shape sudoku.Shape, idx int) []int {
+	row, col := shape.RowCol(idx)
+	cells := []int{}
+
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			r, c := row+dr, col+dc
+
+			if (dr != 0 || dc != 0) && r >= 0 && r < shape.Height() && c >= 0 && c < shape.Width() && shape.Index(r, c) >= 0 {
+				cells = append(cells, shape.Index(r, c))
+			}
+		}
+	}
+
+	return cells
+}
+
+// crosses reports whether a step from one cell to the next would cut across
+// a diagonal step of one of the lines
+func crosses(shape sudoku.Shape, lines [][]int, from int, to int) bool {
+	fromRow, fromCol := shape.RowCol(from)
+	toRow, toCol := shape.RowCol(to)
+
+	if fromRow == toRow || fromCol == toCol {
+		return false
+	}
+
+	a, b := shape.Index(fromRow, toCol), shape.Index(toRow, fromCol)
+
+	for _, line := range lines {
+		for i := 1; i < len(line); i++ {
+			if (line[i-1] == a && line[i] == b) || (line[i-1] == b && line[i] == a) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"math/rand"
+
+	"main/sudoku"
+)
+
+// REGION_TRADES is how many trades each cell sees on average while the
+// boxes are jumbled into jigsaw regions
+const REGION_TRADES = 8
+
+// makeRegions jumbles the boxes of a shape into random connected regions of
+// the same size. Each trade moves a border cell into the neighboring region
+// and takes back a cell of that region touching its own, keeping the trade
+// only if both regions stay in one piece.
+func makeRegions(rng *rand.Rand, shape sudoku.Shape) (sudoku.Shape, error) {
+	regular := shape.Regular()
+	regions := make([]int, regular.NumCells())
+
+	for idx := range regions {
+		regions[idx] = regular.BoxOf(idx)
+	}
+
+	for range REGION_TRADES * len(regions) {
+		a := rng.Intn(len(regions))
+		next := neighbors(regular, a)
+		b := next[rng.Intn(len(next))]
+		from, to := regions[a], regions[b]
+
+		if from == to {
+			continue
+		}
+
+		regions[a] = to
+
+		// Pick the cell given back among those of the other region now touching this one
+		back := []int{}
+
+		for idx, region := range regions {
+			if region != to || idx == a {
+				continue
+			}
+
+			for _, cell := range neighbors(regular, idx) {
+				if regions[cell] == from {
+					back = append(back, idx)
+					break
+				}
+			}
+		}
+
+		if len(back) == 0 {
+			regions[a] = from
+			continue
+		}
+
+		c := back[rng.Intn(len(back))]
+		regions[c] = from
+
+		if !connected(regular, regions, from) || !connected(regular, regions, to) {
+			regions[a], regions[c] = from, to
+		}
+	}
+
+	return regular.WithRegions(regions)
+}
+
+// connected reports whether the cells of a region form a single piece
+func connected(shape sudoku.Shape, regions []int, region int) bool {
+	start := -1
+	size := 0
+
+	for idx := range regions {
+		if regions[idx] == region {
+			size++
+
+			if start < 0 {
+				start = idx
+			}
+		}
+	}
+
+	if start < 0 {
+		return true
+	}
+
+	seen := map[int]bool{start: true}
+	queue := []int{start}
+
+	for len(queue) > 0 {
+		idx := queue[0]
+		queue = queue[1:]
+
+		for _, next := range neighbors(shape, idx) {
+			if !seen[next] && regions[next] == region {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return len(seen) == size
+}
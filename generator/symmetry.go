@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"main/sudoku"
+)
+
+type Symmetry int
+
+const (
+	SymmetryNone Symmetry = iota
+	SymmetryRotational
+	SymmetryMirror
+	SymmetryDiagonal
+)
+
+var symmetryNames = [...]string{"None", "Rotational", "Mirror", "Diagonal"}
+
+func (s Symmetry) String() string {
+	if s < SymmetryNone || s > SymmetryDiagonal {
+		return fmt.Sprintf("Symmetry(%d)", int(s))
+	}
+
+	return symmetryNames[s]
+}
+
+func ParseSymmetry(name string) (Symmetry, error) {
+	for i, symmetryName := range symmetryNames {
+		if strings.EqualFold(name, symmetryName) {
+			return Symmetry(i), nil
+		}
+	}
+
+	return SymmetryNone, fmt.Errorf("unknown symmetry: %s", name)
+}
+
+// mirror returns the cell paired with idx under the symmetry, or -1 where a
+// layout leaves no cell opposite it
+func (s Symmetry) mirror(shape sudoku.Shape, idx int) int {
+	row, col := shape.RowCol(idx)
+	lastRow, lastCol := shape.Height()-1, shape.Width()-1
+
+	switch s {
+	case SymmetryRotational:
+		return shape.Index(lastRow-row, lastCol-col)
+	case SymmetryMirror:
+		return shape.Index(row, lastCol-col)
+	case SymmetryDiagonal:
+		return shape.Index(col, row)
+	default:
+		return idx
+	}
+}
+
+// orbits groups the cells that must be removed together to keep the symmetry
+func (s Symmetry) orbits(shape sudoku.Shape) [][]int {
+	orbits := [][]int{}
+	seen := map[int]bool{}
+
+	for idx := range shape.NumCells() {
+		if seen[idx] {
+			continue
+		}
+
+		orbit := []int{idx}
+		seen[idx] = true
+
+		if pair := s.mirror(shape, idx); pair >= 0 && !seen[pair] {
+			orbit = append(orbit, pair)
+			seen[pair] = true
+		}
+
+		orbits = append(orbits, orbit)
+	}
+
+	return orbits
+}
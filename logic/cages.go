@@ -0,0 +1,79 @@
+package logic
+
+import "main/sudoku"
+
+// findCageCombination narrows the cells of a Killer cage to the digits that
+// appear in some combination reaching its sum which the cells can still hold
+func findCageCombination(s *State) (Step, bool) {
+	for _, cage := range s.Cages {
+		placed := sudoku.Candidates(0)
+		left := cage.Sum
+		empty := []int{}
+
+		for _, idx := range cage.Cells {
+			if value := s.Values[idx]; value != 0 {
+				placed = placed.Add(value)
+				left -= int(value)
+			} else {
+				empty = append(empty, idx)
+			}
+		}
+
+		if len(empty) == 0 {
+			continue
+		}
+
+		allowed := make([]sudoku.Candidates, len(empty))
+
+		for _, combo := range s.Shape.Combinations(len(empty), left) {
+			if combo&placed != 0 || !s.assignable(empty, 0, -1, combo) {
+				continue
+			}
+
+			for i, idx := range empty {
+				for open := s.Candidates[idx] & combo &^ allowed[i]; open != 0; open &= open - 1 {
+					bit := open & -open
+
+					if s.assignable(empty, 0, i, combo&^bit) {
+						allowed[i] |= bit
+					}
+				}
+			}
+		}
+
+		step := Step{Technique: CageCombination, Cells: cage.Cells}
+
+		for i, idx := range empty {
+			for _, digit := range (s.Candidates[idx] &^ allowed[i]).Digits() {
+				step.Eliminations = append(step.Eliminations, Elimination{Cell: idx, Digit: digit})
+				step.Digits = step.Digits.Add(digit)
+			}
+		}
+
+		if len(step.Eliminations) > 0 {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
+
+// assignable reports whether the cells from index from onwards, other than
+// skip, can each take a different digit from the set using their candidates
+func (s *State) assignable(cells []int, from int, skip int, digits sudoku.Candidates) bool {
+	if from == skip {
+		from++
+	}
+
+	if from >= len(cells) {
+		return true
+	}
+
+	for open := s.Candidates[cells[from]] & digits; open != 0; open &= open - 1 {
+		if s.assignable(cells, from+1, skip, digits&^(open&-open)) {
+			return true
+		}
+	}
+
+	return false
+}
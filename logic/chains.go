@@ -0,0 +1,183 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+const maxChainLinks = 16
+
+type chainNode struct {
+	cell  int
+	digit byte
+}
+
+// chainState is a node reached in the search, flagged by whether the next
+// link taken from it must be strong
+type chainState struct {
+	node       chainNode
+	needStrong bool
+}
+
+// strongLinks returns the candidates that must be true if n is false
+func (s *State) strongLinks(n chainNode) []chainNode {
+	links := []chainNode{}
+
+	if s.Candidates[n.cell].Count() == 2 {
+		other, _ := s.Candidates[n.cell].Remove(n.digit).Single()
+		links = append(links, chainNode{cell: n.cell, digit: other})
+	}
+
+	for _, house := range s.Shape.HousesOf(n.cell) {
+		cells := s.cellsWith(s.Shape.House(house), n.digit)
+
+		if len(cells) != 2 {
+			continue
+		}
+
+		other := cells[0]
+
+		if other == n.cell {
+			other = cells[1]
+		}
+
+		links = append(links, chainNode{cell: other, digit: n.digit})
+	}
+
+	return links
+}
+
+// weakLinks returns the candidates that must be false if n is true
+func (s *State) weakLinks(n chainNode) []chainNode {
+	links := []chainNode{}
+
+	for _, digit := range s.Candidates[n.cell].Remove(n.digit).Digits() {
+		links = append(links, chainNode{cell: n.cell, digit: digit})
+	}
+
+	for _, peer := range s.Shape.Peers(n.cell) {
+		if s.Candidates[peer].Has(n.digit) {
+			links = append(links, chainNode{cell: peer, digit: n.digit})
+		}
+	}
+
+	return links
+}
+
+// findAIC searches for chains of alternating strong and weak links that start
+// and end with a strong link. Either end of such a chain must be true, so any
+// candidate disproved by both ends can be eliminated.
+func findAIC(s *State) (Step, bool) {
+	for cell := range s.Shape.NumCells() {
+		for _, digit := range s.Candidates[cell].Digits() {
+			start := chainNode{cell: cell, digit: digit}
+			step, ok := s.searchChain(start)
+
+			if ok {
+				return step, true
+			}
+		}
+	}
+
+	return Step{}, false
+}
+
+func (s *State) searchChain(start chainNode) (Step, bool) {
+	first := chainState{node: start, needStrong: true}
+	parents := map[chainState]chainState{first: first}
+	queue := []chainState{first}
+	depth := map[chainState]int{first: 0}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if depth[current] >= maxChainLinks {
+			continue
+		}
+
+		var links []chainNode
+
+		if current.needStrong {
+			links = s.strongLinks(current.node)
+		} else {
+			links = s.weakLinks(current.node)
+		}
+
+		for _, link := range links {
+			next := chainState{node: link, needStrong: !current.needStrong}
+
+			if _, seen := parents[next]; seen {
+				continue
+			}
+
+			parents[next] = current
+			depth[next] = depth[current] + 1
+			queue = append(queue, next)
+
+			// Chains may only end right after a strong link
+			if next.needStrong || link == start {
+				continue
+			}
+
+			eliminations := s.chainEliminations(start, link)
+
+			if len(eliminations) == 0 {
+				continue
+			}
+
+			cells := []int{}
+			digits := sudoku.Candidates(0)
+
+			for state := next; ; state = parents[state] {
+				if !slices.Contains(cells, state.node.cell) {
+					cells = append([]int{state.node.cell}, cells...)
+				}
+
+				digits = digits.Add(state.node.digit)
+
+				if state == first {
+					break
+				}
+			}
+
+			return Step{
+				Technique:    AIC,
+				Cells:        cells,
+				Digits:       digits,
+				Eliminations: eliminations,
+			}, true
+		}
+	}
+
+	return Step{}, false
+}
+
+// chainEliminations lists the candidates that are false when a or b is true
+func (s *State) chainEliminations(a chainNode, b chainNode) []Elimination {
+	eliminations := []Elimination{}
+
+	switch {
+	case a.digit == b.digit:
+		for _, idx := range s.Shape.Peers(a.cell) {
+			if idx != b.cell && s.Shape.IsPeer(idx, b.cell) && s.Candidates[idx].Has(a.digit) {
+				eliminations = append(eliminations, Elimination{Cell: idx, Digit: a.digit})
+			}
+		}
+	case a.cell == b.cell:
+		for _, digit := range s.Candidates[a.cell].Remove(a.digit).Remove(b.digit).Digits() {
+			eliminations = append(eliminations, Elimination{Cell: a.cell, Digit: digit})
+		}
+	case s.Shape.IsPeer(a.cell, b.cell):
+		if s.Candidates[a.cell].Has(b.digit) {
+			eliminations = append(eliminations, Elimination{Cell: a.cell, Digit: b.digit})
+		}
+
+		if s.Candidates[b.cell].Has(a.digit) {
+			eliminations = append(eliminations, Elimination{Cell: b.cell, Digit: a.digit})
+		}
+	}
+
+	return eliminations
+}
@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// findSimpleColoring two-colors the chains of conjugate pairs for a digit.
+// Two cells of one color seeing each other make that whole color false, and
+// a cell seeing both colors cannot hold the digit.
+func findSimpleColoring(s *State) (Step, bool) {
+	for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+		links := map[int][]int{}
+
+		for house := range len(s.houses) {
+			cells := s.cellsWith(s.houses[house], digit)
+
+			if len(cells) == 2 {
+				links[cells[0]] = append(links[cells[0]], cells[1])
+				links[cells[1]] = append(links[cells[1]], cells[0])
+			}
+		}
+
+		colors := map[int]int{}
+
+		for start := range s.Shape.NumCells() {
+			if _, ok := links[start]; !ok {
+				continue
+			}
+
+			if _, ok := colors[start]; ok {
+				continue
+			}
+
+			// Color the chain reachable from start
+			chain := []int{start}
+			colors[start] = 0
+
+			for i := 0; i < len(chain); i++ {
+				for _, next := range links[chain[i]] {
+					if _, ok := colors[next]; !ok {
+						colors[next] = 1 - colors[chain[i]]
+						chain = append(chain, next)
+					}
+				}
+			}
+
+			if len(chain) < 3 {
+				continue
+			}
+
+			step := Step{
+				Technique: SimpleColoring,
+				Cells:     chain,
+				Digits:    sudoku.CandidatesOf(digit),
+			}
+
+			// Color wrap
+			for _, a := range chain {
+				for _, b := range chain {
+					if a < b && colors[a] == colors[b] && s.Shape.IsPeer(a, b) {
+						for _, idx := range chain {
+							if colors[idx] == colors[a] {
+								step.Eliminations = append(step.Eliminations, Elimination{Cell: idx, Digit: digit})
+							}
+						}
+
+						return step, true
+					}
+				}
+			}
+
+			// Color trap
+			for idx := range s.Shape.NumCells() {
+				if slices.Contains(chain, idx) || !s.Candidates[idx].Has(digit) {
+					continue
+				}
+
+				seen := [2]bool{}
+
+				for _, cell := range chain {
+					if s.Shape.IsPeer(idx, cell) {
+						seen[colors[cell]] = true
+					}
+				}
+
+				if seen[0] && seen[1] {
+					step.Eliminations = append(step.Eliminations, Elimination{Cell: idx, Digit: digit})
+				}
+			}
+
+			if len(step.Eliminations) > 0 {
+				return step, true
+			}
+		}
+	}
+
+	return Step{}, false
+}
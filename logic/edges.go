@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// findEdgeMarker removes the digits of a cell that no digit left across one
+// of its sides could stand beside, whether the side is marked or left blank
+// under the negative constraint
+func findEdgeMarker(s *State) (Step, bool) {
+	for _, constraint := range s.Constraints {
+		edges, ok := constraint.(sudoku.EdgeConstraint)
+
+		if !ok {
+			continue
+		}
+
+		for idx := range s.Shape.NumCells() {
+			if s.Values[idx] != 0 {
+				continue
+			}
+
+			for _, neighbor := range edges.Neighbors(s.Shape, idx) {
+				// Placed digits have already ruled out their neighbors' digits
+				if s.Values[neighbor] != 0 {
+					continue
+				}
+
+				sees := slices.Contains(s.peers(idx), neighbor)
+				allowed := sudoku.Candidates(0)
+
+				for _, digit := range s.Candidates[neighbor].Digits() {
+					beside := s.Shape.AllCandidates() &^ edges.Excluded(s.Shape, idx, neighbor, digit)
+
+					if sees {
+						beside = beside.Remove(digit)
+					}
+
+					allowed |= beside
+				}
+
+				step := Step{Technique: EdgeMarker, Cells: []int{idx, neighbor}}
+
+				for _, digit := range (s.Candidates[idx] &^ allowed).Digits() {
+					step.Eliminations = append(step.Eliminations, Elimination{Cell: idx, Digit: digit})
+					step.Digits = step.Digits.Add(digit)
+				}
+
+				if len(step.Eliminations) > 0 {
+					return step, true
+				}
+			}
+		}
+	}
+
+	return Step{}, false
+}
@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// findFish looks for a digit whose positions in size base lines fall within
+// size cover lines, which removes it from the rest of the cover lines. Each
+// grid is searched on its own, with rows tried as base lines before columns.
+func findFish(s *State, size int) (Step, bool) {
+	technique := map[int]Technique{2: XWing, 3: Swordfish}[size]
+
+	for grid := range s.Shape.NumGrids() {
+		rows, cols := s.Shape.GridRows(grid), s.Shape.GridCols(grid)
+
+		for _, lines := range [][2][]int{{rows, cols}, {cols, rows}} {
+			if step, found := s.findFishIn(technique, size, lines[0], lines[1]); found {
+				return step, true
+			}
+		}
+	}
+
+	return Step{}, false
+}
+
+// findFishIn looks for a fish with base lines among one set of houses and
+// cover lines among another crossing them
+func (s *State) findFishIn(technique Technique, size int, baseLines []int, coverLines []int) (Step, bool) {
+	for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+		bases := []int{}
+
+		for _, line := range baseLines {
+			count := len(s.cellsWith(s.Shape.House(line), digit))
+
+			if count >= 2 && count <= size {
+				bases = append(bases, line)
+			}
+		}
+
+		var step Step
+
+		found := combinations(len(bases), size, func(picked []int) bool {
+			houses := []int{}
+			cells := []int{}
+			covers := []int{}
+
+			for _, i := range picked {
+				houses = append(houses, bases[i])
+
+				for _, idx := range s.cellsWith(s.Shape.House(bases[i]), digit) {
+					cells = append(cells, idx)
+
+					cover := -1
+
+					for _, house := range s.Shape.HousesOf(idx) {
+						if slices.Contains(coverLines, house) {
+							cover = house
+						}
+					}
+
+					if !slices.Contains(covers, cover) {
+						covers = append(covers, cover)
+					}
+				}
+			}
+
+			if len(covers) != size {
+				return false
+			}
+
+			eliminations := []Elimination{}
+
+			for _, cover := range covers {
+				eliminations = append(eliminations, s.eliminations(s.Shape.House(cover), digit, cells)...)
+			}
+
+			if len(eliminations) == 0 {
+				return false
+			}
+
+			step = Step{
+				Technique:    technique,
+				Houses:       append(houses, covers...),
+				Cells:        cells,
+				Digits:       sudoku.CandidatesOf(digit),
+				Eliminations: eliminations,
+			}
+
+			return true
+		})
+
+		if found {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
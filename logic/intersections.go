@@ -0,0 +1,103 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// findPointing looks for a digit confined to one line within a box, which
+// removes it from the rest of that line
+func findPointing(s *State) (Step, bool) {
+	for box := range s.Shape.NumBoxes() {
+		boxHouse := s.Shape.BoxHouse(box)
+
+		for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+			cells := s.cellsWith(s.Shape.House(boxHouse), digit)
+
+			if len(cells) < 2 {
+				continue
+			}
+
+			for _, line := range s.sharedLines(cells) {
+				eliminations := s.eliminations(s.Shape.House(line), digit, cells)
+
+				if len(eliminations) > 0 {
+					return Step{
+						Technique:    Pointing,
+						Houses:       []int{boxHouse, line},
+						Cells:        cells,
+						Digits:       sudoku.CandidatesOf(digit),
+						Eliminations: eliminations,
+					}, true
+				}
+			}
+		}
+	}
+
+	return Step{}, false
+}
+
+// findClaiming looks for a digit confined to one box within a line, which
+// removes it from the rest of that box
+func findClaiming(s *State) (Step, bool) {
+	for _, line := range s.Shape.LineHouses() {
+		for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+			cells := s.cellsWith(s.Shape.House(line), digit)
+
+			if len(cells) < 2 {
+				continue
+			}
+
+			box := s.Shape.BoxOf(cells[0])
+			sameBox := true
+
+			for _, idx := range cells[1:] {
+				sameBox = sameBox && s.Shape.BoxOf(idx) == box
+			}
+
+			if !sameBox {
+				continue
+			}
+
+			boxHouse := s.Shape.BoxHouse(box)
+			eliminations := s.eliminations(s.Shape.House(boxHouse), digit, cells)
+
+			if len(eliminations) > 0 {
+				return Step{
+					Technique:    Claiming,
+					Houses:       []int{line, boxHouse},
+					Cells:        cells,
+					Digits:       sudoku.CandidatesOf(digit),
+					Eliminations: eliminations,
+				}, true
+			}
+		}
+	}
+
+	return Step{}, false
+}
+
+// sharedLines returns the row and column houses containing every cell
+func (s *State) sharedLines(cells []int) []int {
+	lines := []int{}
+	boxHouse := s.Shape.BoxHouse(s.Shape.BoxOf(cells[0]))
+
+	for _, house := range s.Shape.HousesOf(cells[0]) {
+		if house == boxHouse {
+			continue
+		}
+
+		shared := true
+
+		for _, idx := range cells[1:] {
+			shared = shared && slices.Contains(s.Shape.House(house), idx)
+		}
+
+		if shared {
+			lines = append(lines, house)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,155 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// maxLineNodes bounds the partial fillings walked on one line, past which
+// the line is left alone
+const maxLineNodes = 20000
+
+// findLineCombination narrows the cells of each constraint line to the
+// digits that appear in some filling of the line its cells can hold and its
+// rule allows
+func findLineCombination(s *State) (Step, bool) {
+	for _, constraint := range s.Constraints {
+		lines, ok := constraint.(sudoku.LineConstraint)
+
+		if !ok {
+			continue
+		}
+
+		for _, line := range lines.Lines() {
+			allowed, ok := lineAllowed(s, lines, line)
+
+			if !ok {
+				continue
+			}
+
+			step := Step{Technique: LineCombination, Cells: line}
+
+			for i, idx := range line {
+				if s.Values[idx] != 0 {
+					continue
+				}
+
+				for _, digit := range (s.Candidates[idx] &^ allowed[i]).Digits() {
+					step.Eliminations = append(step.Eliminations, Elimination{Cell: idx, Digit: digit})
+					step.Digits = step.Digits.Add(digit)
+				}
+			}
+
+			if len(step.Eliminations) > 0 {
+				return step, true
+			}
+		}
+	}
+
+	return Step{}, false
+}
+
+// lineAllowed lists the digits each cell of a line takes in some filling,
+// or reports false if walking the fillings takes more than maxLineNodes. A
+// palindrome is not walked, as each cell simply shares its mirror's digit.
+func lineAllowed(s *State, rule sudoku.LineConstraint, line []int) ([]sudoku.Candidates, bool) {
+	if _, ok := rule.(sudoku.Palindrome); ok {
+		allowed := make([]sudoku.Candidates, len(line))
+
+		for i, idx := range line {
+			allowed[i] = s.options(idx) & s.options(line[len(line)-1-i])
+		}
+
+		return allowed, true
+	}
+
+	fill := lineFill{
+		state:   s,
+		rule:    rule,
+		line:    line,
+		digits:  sudoku.LineDigits(s.Values, line),
+		allowed: make([]sudoku.Candidates, len(line)),
+		sees:    make([][]bool, len(line)),
+	}
+
+	for i, idx := range line {
+		fill.sees[i] = make([]bool, len(line))
+
+		for j, other := range line {
+			fill.sees[i][j] = slices.Contains(s.peers(idx), other)
+		}
+	}
+
+	fill.try(0)
+
+	return fill.allowed, fill.nodes <= maxLineNodes
+}
+
+// options is the digit placed in a cell, or its candidates while empty
+func (s *State) options(idx int) sudoku.Candidates {
+	if s.Values[idx] != 0 {
+		return sudoku.CandidatesOf(s.Values[idx])
+	}
+
+	return s.Candidates[idx]
+}
+
+// lineFill walks the fillings of one line, gathering the digits each cell
+// takes in any of them. Cells on the line that see each other may not share
+// a digit.
+type lineFill struct {
+	state   *State
+	rule    sudoku.LineConstraint
+	line    []int
+	digits  []byte
+	allowed []sudoku.Candidates
+	sees    [][]bool
+
+	// nodes counts the partial fillings tried, stopping the walk once it
+	// passes maxLineNodes
+	nodes int
+}
+
+// try fills the empty cells from position from onwards, dropping any partial
+// filling the rule already rules out
+func (f *lineFill) try(from int) {
+	f.nodes++
+
+	if f.nodes > maxLineNodes || !f.rule.Allows(f.state.Shape, f.digits) {
+		return
+	}
+
+	for from < len(f.line) && f.state.Values[f.line[from]] != 0 {
+		from++
+	}
+
+	if from == len(f.line) {
+		for i, digit := range f.digits {
+			f.allowed[i] = f.allowed[i].Add(digit)
+		}
+
+		return
+	}
+
+	for _, digit := range f.state.Candidates[f.line[from]].Digits() {
+		if f.repeats(from, digit) {
+			continue
+		}
+
+		f.digits[from] = digit
+		f.try(from + 1)
+	}
+
+	f.digits[from] = 0
+}
+
+func (f *lineFill) repeats(pos int, digit byte) bool {
+	for i, other := range f.digits {
+		if other == digit && f.sees[pos][i] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+package logic_test
+
+import (
+	"testing"
+	"time"
+
+	"main/generator"
+	"main/logic"
+	"main/solver"
+	"main/sudoku"
+)
+
+const classicPuzzle = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+// variants lists generator options for a puzzle of every variant, along
+// with classic puzzles of every grade logic can reach
+func variants() map[string]generator.Options {
+	variants := map[string]generator.Options{
+		"Killer": generator.KillerOptions(sudoku.Classic, sudoku.Medium, 1),
+		"Jigsaw": generator.JigsawOptions(sudoku.Classic, sudoku.Medium, 1),
+	}
+
+	for _, difficulty := range sudoku.Difficulties()[:sudoku.Extreme] {
+		variants["Classic "+difficulty.String()] = generator.DefaultOptions(sudoku.Classic, difficulty, 1)
+	}
+
+	for _, constraint := range sudoku.AllConstraints() {
+		variants[constraint.Name()] = generator.VariantOptions(sudoku.Classic, sudoku.Medium, 1, sudoku.Constraints{constraint})
+	}
+
+	for _, kind := range sudoku.AllLineConstraints() {
+		variants[kind.Name()] = generator.LineOptions(sudoku.Classic, sudoku.Medium, 1, kind)
+	}
+
+	for _, kind := range sudoku.AllEdgeConstraints() {
+		variants[kind.Name()] = generator.EdgeOptions(sudoku.Classic, sudoku.Medium, 1, kind)
+
+		if kind.Negatable() {
+			variants["Negative "+kind.Name()] = generator.EdgeOptions(sudoku.Classic, sudoku.Medium, 1, kind.WithEdges(nil, true))
+		}
+	}
+
+	return variants
+}
+
+// TestStepsSound checks every step logic takes on a puzzle of each variant
+// against the solution found by brute force: placements must put the
+// solution's digit and eliminations must never remove it
+func TestStepsSound(t *testing.T) {
+	for name, opts := range variants() {
+		t.Run(name, func(t *testing.T) {
+			opts.Deadline = time.Now().Add(time.Minute)
+
+			puzzle, err := generator.Generate(opts)
+
+			if err != nil {
+				t.Fatalf("generating puzzle: %s", err)
+			}
+
+			variant := sudoku.Variant{Cages: puzzle.Cages, Constraints: puzzle.Constraints}
+			solution, err := solver.VariantSolution(puzzle.Shape, puzzle.Givens, variant)
+
+			if err != nil {
+				t.Fatalf("solving %s: %s", puzzle.Givens, err)
+			}
+
+			state := logic.NewVariantState(puzzle.Shape, puzzle.Givens, variant)
+
+			for !state.Solved() {
+				step, ok := logic.NextStep(state)
+
+				if !ok {
+					break
+				}
+
+				for _, placement := range step.Placements {
+					if solution[placement.Cell] != placement.Digit {
+						t.Fatalf("%s places %d in cell %d, solution has %d", step.Technique, placement.Digit, placement.Cell, solution[placement.Cell])
+					}
+				}
+
+				for _, elimination := range step.Eliminations {
+					if solution[elimination.Cell] == elimination.Digit {
+						t.Fatalf("%s removes the solution's %d from cell %d", step.Technique, elimination.Digit, elimination.Cell)
+					}
+				}
+
+				if len(step.Placements) == 0 && len(step.Eliminations) == 0 {
+					t.Fatalf("%s made no progress", step.Technique)
+				}
+
+				state.Apply(step)
+			}
+
+			if !state.Solved() {
+				t.Fatalf("logic did not finish a %s puzzle", puzzle.Difficulty)
+			}
+
+			if state.Values.String() != solution.String() {
+				t.Errorf("logic solved to %s, solution is %s", state.Values, solution)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	givens, err := sudoku.ParseGrid(sudoku.Classic, classicPuzzle)
+
+	if err != nil {
+		t.Fatalf("parsing grid: %s", err)
+	}
+
+	result := logic.Solve(sudoku.Classic, givens)
+
+	if !result.Solved {
+		t.Fatalf("puzzle was not solved")
+	}
+
+	if result.Difficulty != sudoku.Easy {
+		t.Errorf("puzzle solved by singles graded %s", result.Difficulty)
+	}
+
+	for _, step := range result.Steps {
+		if step.Technique.Difficulty() != sudoku.Easy {
+			t.Errorf("easy puzzle took %s", step.Technique)
+		}
+	}
+}
+
+// TestGradeUnsolved checks a grid logic cannot finish is graded Extreme
+func TestGradeUnsolved(t *testing.T) {
+	if difficulty := logic.Grade(sudoku.Classic, sudoku.NewGrid(sudoku.Classic)); difficulty != sudoku.Extreme {
+		t.Errorf("empty grid graded %s, want Extreme", difficulty)
+	}
+}
@@ -0,0 +1,44 @@
+package logic
+
+import "main/sudoku"
+
+func findNakedSingle(s *State) (Step, bool) {
+	for idx := range s.Shape.NumCells() {
+		digit, ok := s.Candidates[idx].Single()
+
+		if s.Values[idx] != 0 || !ok {
+			continue
+		}
+
+		return Step{
+			Technique:  NakedSingle,
+			Cells:      []int{idx},
+			Digits:     sudoku.CandidatesOf(digit),
+			Placements: []Placement{{Cell: idx, Digit: digit}},
+		}, true
+	}
+
+	return Step{}, false
+}
+
+func findHiddenSingle(s *State) (Step, bool) {
+	for house := range len(s.houses) {
+		for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+			cells := s.cellsWith(s.houses[house], digit)
+
+			if len(cells) != 1 {
+				continue
+			}
+
+			return Step{
+				Technique:  HiddenSingle,
+				Houses:     []int{house},
+				Cells:      cells,
+				Digits:     sudoku.CandidatesOf(digit),
+				Placements: []Placement{{Cell: cells[0], Digit: digit}},
+			}, true
+		}
+	}
+
+	return Step{}, false
+}
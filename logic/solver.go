@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"main/sudoku"
+)
+
+type finder func(*State) (Step, bool)
+
+var finders = map[Technique]finder{
+	NakedSingle:     findNakedSingle,
+	HiddenSingle:    findHiddenSingle,
+	CageCombination: findCageCombination,
+	LineCombination: findLineCombination,
+	EdgeMarker:      findEdgeMarker,
+	Pointing:        findPointing,
+	Claiming:        findClaiming,
+	NakedPair:       func(s *State) (Step, bool) { return findNakedSubset(s, 2) },
+	HiddenPair:      func(s *State) (Step, bool) { return findHiddenSubset(s, 2) },
+	NakedTriple:     func(s *State) (Step, bool) { return findNakedSubset(s, 3) },
+	HiddenTriple:    func(s *State) (Step, bool) { return findHiddenSubset(s, 3) },
+	XWing:           func(s *State) (Step, bool) { return findFish(s, 2) },
+	Swordfish:       func(s *State) (Step, bool) { return findFish(s, 3) },
+	XYWing:          findXYWing,
+	SimpleColoring:  findSimpleColoring,
+	AIC:             findAIC,
+}
+
+// Result lists the steps taken to solve a puzzle and the grade they earn it
+type Result struct {
+	Steps      []Step
+	Grid       sudoku.Grid
+	Solved     bool
+	Difficulty sudoku.Difficulty
+}
+
+// NextStep finds the simplest deduction available in the state
+func NextStep(state *State) (Step, bool) {
+	for _, technique := range Techniques() {
+		step, ok := finders[technique](state)
+
+		if ok {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
+
+func Solve(shape sudoku.Shape, grid sudoku.Grid) Result {
+	return SolveKiller(shape, grid, nil)
+}
+
+// SolveKiller is Solve for a Killer puzzle, reasoning about the cages as well
+func SolveKiller(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage) Result {
+	return SolveVariant(shape, grid, sudoku.Variant{Cages: cages})
+}
+
+// SolveVariant is Solve for a puzzle with cages and constraints
+func SolveVariant(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant) Result {
+	state := NewVariantState(shape, grid, variant)
+	result := Result{Difficulty: sudoku.Easy}
+
+	for !state.Solved() {
+		step, ok := NextStep(state)
+
+		if !ok {
+			break
+		}
+
+		state.Apply(step)
+		result.Steps = append(result.Steps, step)
+		result.Difficulty = max(result.Difficulty, step.Technique.Difficulty())
+	}
+
+	result.Grid = state.Values
+	result.Solved = state.Solved()
+
+	// Puzzles the catalog cannot finish need guessing
+	if !result.Solved {
+		result.Difficulty = sudoku.Extreme
+	}
+
+	return result
+}
+
+func Grade(shape sudoku.Shape, grid sudoku.Grid) sudoku.Difficulty {
+	return Solve(shape, grid).Difficulty
+}
+
+func GradeKiller(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage) sudoku.Difficulty {
+	return SolveKiller(shape, grid, cages).Difficulty
+}
+
+func GradeVariant(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant) sudoku.Difficulty {
+	return SolveVariant(shape, grid, variant).Difficulty
+}
@@ -0,0 +1,256 @@
+package logic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"main/sudoku"
+)
+
+type Placement struct {
+	Cell  int
+	Digit byte
+}
+
+type Elimination struct {
+	Cell  int
+	Digit byte
+}
+
+// Step records one deduction along with the houses, cells and digits that prove it
+type Step struct {
+	Technique Technique
+
+	Houses []int
+	Cells  []int
+	Digits sudoku.Candidates
+
+	Placements   []Placement
+	Eliminations []Elimination
+}
+
+// Describe explains the step in terms of the cells of a shape and the
+// houses its constraints add
+func (s Step) Describe(shape sudoku.Shape, constraints sudoku.Constraints) string {
+	parts := []string{s.Technique.String()}
+
+	if !s.Digits.Empty() {
+		names := []string{}
+
+		for _, digit := range s.Digits.Digits() {
+			names = append(names, sudoku.DigitName(digit))
+		}
+
+		parts = append(parts, "on ["+strings.Join(names, " ")+"]")
+	}
+
+	if len(s.Houses) > 0 {
+		names := []string{}
+
+		for _, house := range s.Houses {
+			names = append(names, constraints.HouseName(shape, house))
+		}
+
+		parts = append(parts, "in "+strings.Join(names, ", "))
+	}
+
+	results := []string{}
+
+	for _, placement := range s.Placements {
+		results = append(results, fmt.Sprintf("%s=%s", shape.CellName(placement.Cell), sudoku.DigitName(placement.Digit)))
+	}
+
+	for _, elimination := range s.Eliminations {
+		results = append(results, fmt.Sprintf("%s<>%s", shape.CellName(elimination.Cell), sudoku.DigitName(elimination.Digit)))
+	}
+
+	return strings.Join(parts, " ") + ": " + strings.Join(results, ", ")
+}
+
+// State is a grid with explicit candidates, narrowed as steps are applied
+type State struct {
+	Shape      sudoku.Shape
+	Values     sudoku.Grid
+	Candidates []sudoku.Candidates
+
+	// Cages are the Killer cages of the puzzle, with cageOf holding the
+	// index of the cage each cell is in or -1
+	Cages  []sudoku.Cage
+	cageOf []int
+
+	// Constraints are the variant rules of the puzzle, and houses the
+	// houses of the shape followed by those the constraints add
+	Constraints sudoku.Constraints
+	houses      [][]int
+}
+
+func NewState(shape sudoku.Shape, grid sudoku.Grid) *State {
+	return NewVariantState(shape, grid, sudoku.Variant{})
+}
+
+// NewKillerState is NewState for a Killer puzzle, whose cages the caller
+// has already validated
+func NewKillerState(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage) *State {
+	return NewVariantState(shape, grid, sudoku.Variant{Cages: cages})
+}
+
+// NewVariantState is NewState for a puzzle with cages and constraints, which
+// the caller has already validated
+func NewVariantState(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant) *State {
+	s := &State{
+		Shape:       shape,
+		Values:      grid.Clone(),
+		Candidates:  make([]sudoku.Candidates, len(grid)),
+		Cages:       variant.Cages,
+		cageOf:      make([]int, len(grid)),
+		Constraints: variant.Constraints,
+		houses:      variant.Constraints.Houses(shape),
+	}
+
+	for idx := range s.cageOf {
+		s.cageOf[idx] = -1
+	}
+
+	for i, cage := range s.Cages {
+		for _, idx := range cage.Cells {
+			s.cageOf[idx] = i
+		}
+	}
+
+	for idx := range grid {
+		if grid[idx] != 0 {
+			continue
+		}
+
+		candidates := shape.AllCandidates()
+
+		for _, peer := range s.peers(idx) {
+			candidates = candidates.Remove(grid[peer])
+		}
+
+		s.Candidates[idx] = candidates &^ s.Constraints.Excluded(shape, grid, idx)
+	}
+
+	return s
+}
+
+func (s *State) Place(idx int, digit byte) {
+	s.Values[idx] = digit
+	s.Candidates[idx] = 0
+
+	for _, peer := range s.peers(idx) {
+		s.Candidates[peer] = s.Candidates[peer].Remove(digit)
+	}
+
+	for _, constraint := range s.Constraints {
+		for _, neighbor := range constraint.Neighbors(s.Shape, idx) {
+			s.Candidates[neighbor] &^= constraint.Excluded(s.Shape, neighbor, idx, digit)
+		}
+	}
+}
+
+// peers lists the house peers of a cell along with the rest of its cage and
+// of the houses the constraints add
+func (s *State) peers(idx int) []int {
+	cage := s.cageOf[idx]
+
+	if cage < 0 && len(s.houses) == s.Shape.NumHouses() {
+		return s.Shape.Peers(idx)
+	}
+
+	peers := slices.Clone(s.Shape.Peers(idx))
+	groups := [][]int{}
+
+	if cage >= 0 {
+		groups = append(groups, s.Cages[cage].Cells)
+	}
+
+	for _, house := range s.houses[s.Shape.NumHouses():] {
+		if slices.Contains(house, idx) {
+			groups = append(groups, house)
+		}
+	}
+
+	for _, group := range groups {
+		for _, cell := range group {
+			if cell != idx && !slices.Contains(peers, cell) {
+				peers = append(peers, cell)
+			}
+		}
+	}
+
+	return peers
+}
+
+func (s *State) Eliminate(idx int, digit byte) {
+	s.Candidates[idx] = s.Candidates[idx].Remove(digit)
+}
+
+func (s *State) Apply(step Step) {
+	for _, placement := range step.Placements {
+		s.Place(placement.Cell, placement.Digit)
+	}
+
+	for _, elimination := range step.Eliminations {
+		s.Eliminate(elimination.Cell, elimination.Digit)
+	}
+}
+
+func (s *State) Solved() bool {
+	return s.Values.Clues() == len(s.Values)
+}
+
+// cellsWith lists the cells of a house still holding the digit as a candidate
+func (s *State) cellsWith(house []int, digit byte) []int {
+	cells := []int{}
+
+	for _, idx := range house {
+		if s.Candidates[idx].Has(digit) {
+			cells = append(cells, idx)
+		}
+	}
+
+	return cells
+}
+
+// eliminations collects the candidates for digit in cells, skipping cells in except
+func (s *State) eliminations(cells []int, digit byte, except []int) []Elimination {
+	eliminations := []Elimination{}
+
+	for _, idx := range cells {
+		if slices.Contains(except, idx) || !s.Candidates[idx].Has(digit) {
+			continue
+		}
+
+		eliminations = append(eliminations, Elimination{Cell: idx, Digit: digit})
+	}
+
+	return eliminations
+}
+
+// combinations calls fn with every k-sized subset of indices into n items
+// until fn returns true
+func combinations(n int, k int, fn func([]int) bool) bool {
+	picked := make([]int, k)
+
+	var pick func(start int, depth int) bool
+
+	pick = func(start int, depth int) bool {
+		if depth == k {
+			return fn(picked)
+		}
+
+		for i := start; i <= n-(k-depth); i++ {
+			picked[depth] = i
+
+			if pick(i+1, depth+1) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return pick(0, 0)
+}
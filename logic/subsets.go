@@ -0,0 +1,136 @@
+package logic
+
+import (
+	"slices"
+
+	"main/sudoku"
+)
+
+// findNakedSubset looks for size cells in a house sharing exactly size
+// candidates, which removes those candidates from the rest of the house
+func findNakedSubset(s *State, size int) (Step, bool) {
+	technique := map[int]Technique{2: NakedPair, 3: NakedTriple}[size]
+
+	for house := range len(s.houses) {
+		open := []int{}
+
+		for _, idx := range s.houses[house] {
+			count := s.Candidates[idx].Count()
+
+			if s.Values[idx] == 0 && count >= 2 && count <= size {
+				open = append(open, idx)
+			}
+		}
+
+		var step Step
+
+		found := combinations(len(open), size, func(picked []int) bool {
+			cells := []int{}
+			union := sudoku.Candidates(0)
+
+			for _, i := range picked {
+				cells = append(cells, open[i])
+				union |= s.Candidates[open[i]]
+			}
+
+			if union.Count() != size {
+				return false
+			}
+
+			eliminations := []Elimination{}
+
+			for _, digit := range union.Digits() {
+				eliminations = append(eliminations, s.eliminations(s.houses[house], digit, cells)...)
+			}
+
+			if len(eliminations) == 0 {
+				return false
+			}
+
+			step = Step{
+				Technique:    technique,
+				Houses:       []int{house},
+				Cells:        cells,
+				Digits:       union,
+				Eliminations: eliminations,
+			}
+
+			return true
+		})
+
+		if found {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
+
+// findHiddenSubset looks for size digits confined to the same size cells of a
+// house, which removes every other candidate from those cells
+func findHiddenSubset(s *State, size int) (Step, bool) {
+	technique := map[int]Technique{2: HiddenPair, 3: HiddenTriple}[size]
+
+	for house := range len(s.houses) {
+		digits := []byte{}
+		positions := map[byte][]int{}
+
+		for digit := byte(1); int(digit) <= s.Shape.Size(); digit++ {
+			cells := s.cellsWith(s.houses[house], digit)
+
+			if len(cells) >= 2 && len(cells) <= size {
+				digits = append(digits, digit)
+				positions[digit] = cells
+			}
+		}
+
+		var step Step
+
+		found := combinations(len(digits), size, func(picked []int) bool {
+			set := sudoku.Candidates(0)
+			cells := []int{}
+
+			for _, i := range picked {
+				set = set.Add(digits[i])
+
+				for _, idx := range positions[digits[i]] {
+					if !slices.Contains(cells, idx) {
+						cells = append(cells, idx)
+					}
+				}
+			}
+
+			if len(cells) != size {
+				return false
+			}
+
+			eliminations := []Elimination{}
+
+			for _, idx := range cells {
+				for _, digit := range (s.Candidates[idx] &^ set).Digits() {
+					eliminations = append(eliminations, Elimination{Cell: idx, Digit: digit})
+				}
+			}
+
+			if len(eliminations) == 0 {
+				return false
+			}
+
+			step = Step{
+				Technique:    technique,
+				Houses:       []int{house},
+				Cells:        cells,
+				Digits:       set,
+				Eliminations: eliminations,
+			}
+
+			return true
+		})
+
+		if found {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"fmt"
+
+	"main/sudoku"
+)
+
+// Technique names a deduction, in the order the step solver tries them
+type Technique int
+
+const (
+	NakedSingle Technique = iota
+	HiddenSingle
+	CageCombination
+	LineCombination
+	EdgeMarker
+	Pointing
+	Claiming
+	NakedPair
+	HiddenPair
+	NakedTriple
+	HiddenTriple
+	XWing
+	Swordfish
+	XYWing
+	SimpleColoring
+	AIC
+)
+
+var techniqueNames = [...]string{
+	"Naked Single",
+	"Hidden Single",
+	"Cage Combination",
+	"Line Combination",
+	"Edge Marker",
+	"Pointing",
+	"Claiming",
+	"Naked Pair",
+	"Hidden Pair",
+	"Naked Triple",
+	"Hidden Triple",
+	"X-Wing",
+	"Swordfish",
+	"XY-Wing",
+	"Simple Coloring",
+	"Alternating Inference Chain",
+}
+
+func Techniques() []Technique {
+	techniques := []Technique{}
+
+	for t := NakedSingle; t <= AIC; t++ {
+		techniques = append(techniques, t)
+	}
+
+	return techniques
+}
+
+func (t Technique) String() string {
+	if t < NakedSingle || t > AIC {
+		return fmt.Sprintf("Technique(%d)", int(t))
+	}
+
+	return techniqueNames[t]
+}
+
+// Difficulty is the grade of a puzzle whose hardest required technique is t
+func (t Technique) Difficulty() sudoku.Difficulty {
+	switch {
+	case t <= EdgeMarker:
+		return sudoku.Easy
+	case t <= HiddenTriple:
+		return sudoku.Medium
+	case t <= XYWing:
+		return sudoku.Hard
+	default:
+		return sudoku.Expert
+	}
+}
@@ -0,0 +1,287 @@
+package logic
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"main/sudoku"
+)
+
+// Houses of the classic grid, numbered rows first, then columns, then boxes
+const (
+	row0 = 0
+	row3 = 3
+	row4 = 4
+	row6 = 6
+	col4 = 13
+	box0 = 18
+)
+
+// fixture is a state set up so that one technique finds exactly one step
+type fixture struct {
+	technique    Technique
+	variant      sudoku.Variant
+	setup        func(s *State)
+	placements   []Placement
+	eliminations []Elimination
+}
+
+// confine removes a digit from every cell of a house but the given ones
+func confine(s *State, house int, digit byte, cells ...int) {
+	for _, idx := range s.Shape.House(house) {
+		if !slices.Contains(cells, idx) {
+			s.Eliminate(idx, digit)
+		}
+	}
+}
+
+// set gives cells exactly the listed candidates
+func set(s *State, candidates map[int][]byte) {
+	for idx, digits := range candidates {
+		s.Candidates[idx] = sudoku.CandidatesOf(digits...)
+	}
+}
+
+// eliminate lists the eliminations of each digit from each cell
+func eliminate(cells []int, digits ...byte) []Elimination {
+	eliminations := []Elimination{}
+
+	for _, idx := range cells {
+		for _, digit := range digits {
+			eliminations = append(eliminations, Elimination{Cell: idx, Digit: digit})
+		}
+	}
+
+	return eliminations
+}
+
+// sorted orders eliminations by cell and digit, as finders list them in
+// the order they search
+func sorted(eliminations []Elimination) []Elimination {
+	eliminations = slices.Clone(eliminations)
+
+	slices.SortFunc(eliminations, func(a Elimination, b Elimination) int {
+		return cmp.Or(cmp.Compare(a.Cell, b.Cell), cmp.Compare(a.Digit, b.Digit))
+	})
+
+	return eliminations
+}
+
+// fixtures sets up each technique on an otherwise open classic grid, where
+// every other cell keeps all nine candidates and so takes no part
+func fixtures() []fixture {
+	return []fixture{
+		{
+			technique: NakedSingle,
+			setup: func(s *State) {
+				set(s, map[int][]byte{40: {5}})
+			},
+			placements: []Placement{{Cell: 40, Digit: 5}},
+		},
+		{
+			technique: HiddenSingle,
+			setup: func(s *State) {
+				confine(s, row0, 5, 4)
+			},
+			placements: []Placement{{Cell: 4, Digit: 5}},
+		},
+		{
+			// Two cells of a cage adding up to 3 hold a 1 and a 2
+			technique: CageCombination,
+			variant: sudoku.Variant{
+				Cages: []sudoku.Cage{{Cells: []int{0, 1}, Sum: 3}},
+			},
+			eliminations: eliminate([]int{0, 1}, 3, 4, 5, 6, 7, 8, 9),
+		},
+		{
+			// Digits rise along a thermometer of three cells, leaving room
+			// above the bulb and below the tip
+			technique: LineCombination,
+			variant: sudoku.Variant{
+				Constraints: sudoku.Constraints{sudoku.Thermo{}.WithLines([][]int{{0, 1, 2}})},
+			},
+			eliminations: []Elimination{
+				{Cell: 0, Digit: 8}, {Cell: 0, Digit: 9},
+				{Cell: 1, Digit: 1}, {Cell: 1, Digit: 9},
+				{Cell: 2, Digit: 1}, {Cell: 2, Digit: 2},
+			},
+		},
+		{
+			// Neighbors adding up to 10 in one row cannot both be 5
+			technique: EdgeMarker,
+			variant: sudoku.Variant{
+				Constraints: sudoku.Constraints{sudoku.XV{}.WithEdges([]sudoku.Edge{{Cells: [2]int{0, 1}, Marker: sudoku.XMark}}, false)},
+			},
+			eliminations: eliminate([]int{0}, 5),
+		},
+		{
+			technique: Pointing,
+			setup: func(s *State) {
+				confine(s, box0, 1, 0, 1)
+			},
+			eliminations: eliminate([]int{3, 4, 5, 6, 7, 8}, 1),
+		},
+		{
+			technique: Claiming,
+			setup: func(s *State) {
+				confine(s, row0, 1, 0, 1)
+			},
+			eliminations: eliminate([]int{9, 10, 11, 18, 19, 20}, 1),
+		},
+		{
+			technique: NakedPair,
+			setup: func(s *State) {
+				set(s, map[int][]byte{0: {1, 2}, 1: {1, 2}})
+			},
+			eliminations: eliminate([]int{2, 3, 4, 5, 6, 7, 8}, 1, 2),
+		},
+		{
+			technique: HiddenPair,
+			setup: func(s *State) {
+				confine(s, row0, 1, 0, 1)
+				confine(s, row0, 2, 0, 1)
+			},
+			eliminations: eliminate([]int{0, 1}, 3, 4, 5, 6, 7, 8, 9),
+		},
+		{
+			technique: NakedTriple,
+			setup: func(s *State) {
+				set(s, map[int][]byte{0: {1, 2}, 1: {2, 3}, 2: {1, 3}})
+			},
+			eliminations: eliminate([]int{3, 4, 5, 6, 7, 8}, 1, 2, 3),
+		},
+		{
+			technique: HiddenTriple,
+			setup: func(s *State) {
+				for digit := byte(1); digit <= 3; digit++ {
+					confine(s, row0, digit, 0, 1, 2)
+				}
+			},
+			eliminations: eliminate([]int{0, 1, 2}, 4, 5, 6, 7, 8, 9),
+		},
+		{
+			// Rows 1 and 5 hold their 1 in columns 1 and 5
+			technique: XWing,
+			setup: func(s *State) {
+				confine(s, row0, 1, 0, 4)
+				confine(s, row4, 1, 36, 40)
+			},
+			eliminations: eliminate([]int{9, 18, 27, 45, 54, 63, 72, 13, 22, 31, 49, 58, 67, 76}, 1),
+		},
+		{
+			// Rows 1, 4 and 7 hold their 1 in columns 1, 4 and 7
+			technique: Swordfish,
+			setup: func(s *State) {
+				confine(s, row0, 1, 0, 3)
+				confine(s, row3, 1, 30, 33)
+				confine(s, row6, 1, 54, 60)
+			},
+			eliminations: eliminate([]int{
+				9, 18, 36, 45, 63, 72,
+				12, 21, 39, 48, 66, 75,
+				15, 24, 42, 51, 69, 78,
+			}, 1),
+		},
+		{
+			// The pivot r1c1 sees the pincers r1c5 and r3c1, which remove 3
+			// from the cells seeing both
+			technique: XYWing,
+			setup: func(s *State) {
+				set(s, map[int][]byte{0: {1, 2}, 4: {1, 3}, 18: {2, 3}})
+			},
+			eliminations: eliminate([]int{1, 2, 21, 22, 23}, 3),
+		},
+		{
+			// The 1s of r1c1 and r5c1 take opposite colors along the pairs
+			// in row 1, column 5 and row 5, trapping the rest of column 1
+			technique: SimpleColoring,
+			setup: func(s *State) {
+				confine(s, row0, 1, 0, 4)
+				confine(s, col4, 1, 4, 40)
+				confine(s, row4, 1, 36, 40)
+			},
+			eliminations: eliminate([]int{9, 18, 27, 45, 54, 63, 72}, 1),
+		},
+		{
+			// The chain r1c1 1=2 r1c5 2=3 r5c5 3=4 r5c1 4=1 has a 1 at
+			// either end, so no other cell of column 1 holds one
+			technique: AIC,
+			setup: func(s *State) {
+				set(s, map[int][]byte{0: {1, 2}, 4: {2, 3}, 40: {3, 4}, 36: {4, 1}})
+			},
+			eliminations: eliminate([]int{9, 18, 27, 45, 54, 63, 72}, 1),
+		},
+	}
+}
+
+// TestTechniques checks each technique finds the step of its fixture, with
+// exactly its placements and eliminations
+func TestTechniques(t *testing.T) {
+	tested := map[Technique]bool{}
+
+	for _, f := range fixtures() {
+		t.Run(f.technique.String(), func(t *testing.T) {
+			tested[f.technique] = true
+
+			state := NewVariantState(sudoku.Classic, sudoku.NewGrid(sudoku.Classic), f.variant)
+
+			if f.setup != nil {
+				f.setup(state)
+			}
+
+			step, ok := finders[f.technique](state)
+
+			if !ok {
+				t.Fatalf("%s found no step", f.technique)
+			}
+
+			if step.Technique != f.technique {
+				t.Errorf("step is %s, want %s", step.Technique, f.technique)
+			}
+
+			if !slices.Equal(step.Placements, f.placements) {
+				t.Errorf("placements %v, want %v", step.Placements, f.placements)
+			}
+
+			if got, want := sorted(step.Eliminations), sorted(f.eliminations); !slices.Equal(got, want) {
+				t.Errorf("eliminations %v, want %v", got, want)
+			}
+		})
+	}
+
+	for _, technique := range Techniques() {
+		if !tested[technique] {
+			t.Errorf("no fixture for %s", technique)
+		}
+	}
+}
+
+// TestPalindromeMirror checks a palindrome too long to walk still narrows
+// each cell to the digits its mirror can hold
+func TestPalindromeMirror(t *testing.T) {
+	// Row 1 runs out along the line and row 5 back, three columns over
+	line := make([]int, 18)
+
+	for i := range 9 {
+		line[i] = i
+		line[17-i] = 36 + (i+3)%9
+	}
+
+	variant := sudoku.Variant{
+		Constraints: sudoku.Constraints{sudoku.Palindrome{}.WithLines([][]int{line})},
+	}
+
+	state := NewVariantState(sudoku.Classic, sudoku.NewGrid(sudoku.Classic), variant)
+	set(state, map[int][]byte{0: {1, 2}})
+
+	step, ok := findLineCombination(state)
+
+	if !ok {
+		t.Fatalf("palindrome found no step")
+	}
+
+	if got, want := sorted(step.Eliminations), eliminate([]int{39}, 3, 4, 5, 6, 7, 8, 9); !slices.Equal(got, want) {
+		t.Errorf("eliminations %v, want %v", got, want)
+	}
+}
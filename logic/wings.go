@@ -0,0 +1,49 @@
+package logic
+
+import "main/sudoku"
+
+// findXYWing looks for a bivalue pivot {x,y} seeing pincers {x,z} and {y,z},
+// which removes z from every cell seeing both pincers
+func findXYWing(s *State) (Step, bool) {
+	for pivot := range s.Shape.NumCells() {
+		if s.Candidates[pivot].Count() != 2 {
+			continue
+		}
+
+		digits := s.Candidates[pivot].Digits()
+		x, y := digits[0], digits[1]
+
+		for _, a := range s.Shape.Peers(pivot) {
+			if s.Candidates[a].Count() != 2 || !s.Candidates[a].Has(x) || s.Candidates[a].Has(y) {
+				continue
+			}
+
+			z, _ := s.Candidates[a].Remove(x).Single()
+
+			for _, b := range s.Shape.Peers(pivot) {
+				if b == a || s.Candidates[b] != sudoku.CandidatesOf(y, z) {
+					continue
+				}
+
+				eliminations := []Elimination{}
+
+				for _, idx := range s.Shape.Peers(a) {
+					if idx != pivot && idx != b && s.Shape.IsPeer(idx, b) && s.Candidates[idx].Has(z) {
+						eliminations = append(eliminations, Elimination{Cell: idx, Digit: z})
+					}
+				}
+
+				if len(eliminations) > 0 {
+					return Step{
+						Technique:    XYWing,
+						Cells:        []int{pivot, a, b},
+						Digits:       sudoku.CandidatesOf(x, y, z),
+						Eliminations: eliminations,
+					}, true
+				}
+			}
+		}
+	}
+
+	return Step{}, false
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"time"
 
@@ -12,6 +13,10 @@ import (
 )
 
 func main() {
+	scale := flag.Float64("scale", 0, "size of the interface, such as 2 for high-DPI displays (default from the display)")
+	fullscreen := flag.Bool("fullscreen", false, "start filling the screen")
+	flag.Parse()
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		log.Fatalf("Error initializing SDL: %s\n", err)
 	}
@@ -22,13 +27,27 @@ func main() {
 	}
 	defer ttf.Quit()
 
-	wind_width, wind_height := int32(800), int32(600)
-	window, err := sdl.CreateWindow("Sudoku", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, wind_width, wind_height, sdl.WINDOW_SHOWN)
+	if *scale == 0 {
+		*scale = engine.DisplayScale(0)
+	}
+
+	*scale = engine.ClampScale(*scale)
+
+	window_flags := uint32(sdl.WINDOW_SHOWN | sdl.WINDOW_RESIZABLE)
+
+	if *fullscreen {
+		window_flags |= sdl.WINDOW_FULLSCREEN_DESKTOP
+	}
+
+	wind_width, wind_height := engine.WindowSize(*scale)
+	window, err := sdl.CreateWindow("Sudoku", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, wind_width, wind_height, window_flags)
 	if err != nil {
 		log.Fatalf("Error creating window: %s\n", err)
 	}
 	defer window.Destroy()
 
+	window.SetMinimumSize(wind_width, wind_height)
+
 	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED|sdl.RENDERER_PRESENTVSYNC)
 	if err != nil {
 		log.Fatalf("Error creating renderer: %s\n", err)
@@ -37,12 +56,21 @@ func main() {
 
 	appEngine := &engine.Engine{}
 
-	err = appEngine.Setup(window, renderer)
+	err = appEngine.Setup(window, renderer, *scale)
 
 	if err != nil {
 		log.Fatalf("Error starting engine: %s\n", err)
 	}
 
+	// A puzzle file given on the command line opens straight into a game
+	if flag.NArg() > 0 {
+		err = appEngine.OpenFile(flag.Arg(0))
+
+		if err != nil {
+			log.Printf("Error opening puzzle: %s\n", err)
+		}
+	}
+
 	running := true
 	for running {
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
@@ -60,6 +88,32 @@ func main() {
 				press_state, _ := selection.Ternary(t.X == 0, selection.Ternary(t.Y > 0, engine.PRESSED, engine.RELEASED), selection.Ternary(t.X > 0, engine.PRESSED, engine.RELEASED)).(byte)
 				args := []interface{}{t.X, t.Y}
 				appEngine.ProcessAction(action_byte, press_state, args)
+			case *sdl.KeyboardEvent:
+				press_state, _ := selection.Ternary(t.Type == sdl.KEYDOWN, engine.PRESSED, engine.RELEASED).(byte)
+				err := appEngine.ProcessKey(t.Keysym.Sym, t.Keysym.Mod, press_state, t.Repeat != 0)
+
+				if err != nil {
+					log.Printf("Error processing key: %s\n", err)
+				}
+			case *sdl.TextInputEvent:
+				appEngine.TextInput(t.GetText())
+			case *sdl.WindowEvent:
+				switch t.Event {
+				case sdl.WINDOWEVENT_FOCUS_GAINED:
+					appEngine.Focused = true
+				case sdl.WINDOWEVENT_FOCUS_LOST:
+					appEngine.Focused = false
+				case sdl.WINDOWEVENT_SIZE_CHANGED:
+					appEngine.Resized()
+				}
+			case *sdl.DropEvent:
+				if t.Type == sdl.DROPFILE {
+					err := appEngine.OpenFile(t.File)
+
+					if err != nil {
+						log.Printf("Error opening puzzle: %s\n", err)
+					}
+				}
 			}
 		}
 
@@ -78,5 +132,6 @@ func main() {
 		}
 	}
 
+	appEngine.Quit()
 	appEngine.FreeFonts()
 }
@@ -0,0 +1,472 @@
+package solver
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+	"time"
+
+	"main/sudoku"
+)
+
+// Stats describes the work done by a search
+type Stats struct {
+	Nodes      int
+	Guesses    int
+	Backtracks int
+	Elapsed    time.Duration
+}
+
+// Result holds the first solution found and the number of solutions counted,
+// which never exceeds the limit passed to Solve
+type Result struct {
+	Solution sudoku.Grid
+	Count    int
+	Stats    Stats
+}
+
+// cage tracks the digits placed so far in a Killer cage, out of the digits
+// the grid uses
+type cage struct {
+	digits uint16
+	sum    int
+	size   int
+	used   uint16
+	total  int
+	filled int
+}
+
+// link ties a cell to a neighbor under a constraint, with the digits each
+// digit of the neighbor rules out for the cell
+type link struct {
+	cell     int
+	excluded [sudoku.MaxSize + 1]uint16
+}
+
+// rule is a line through a cell whose digits are checked together, since its
+// rule reaches beyond pairs of cells
+type rule struct {
+	constraint sudoku.LineConstraint
+	cells      []int
+}
+
+type search struct {
+	shape sudoku.Shape
+	all   uint16
+	grid  sudoku.Grid
+
+	cages  []cage
+	cageOf []int
+
+	// houses holds the digits placed in each house of the shape and those
+	// the constraints add, with houseOf listing the houses each cell is in
+	// and houseCells the cells of each house. A cell shared by the grids of
+	// a layout is in the houses of each.
+	houses     []uint16
+	houseOf    [][]int
+	houseCells [][]int
+	links      [][]link
+
+	// open is scratch space for the candidates of each empty cell
+	open []uint16
+
+	// rules lists the lines through each cell, and digits is scratch space
+	// for the digits along one
+	rules  [][]rule
+	digits []byte
+
+	limit  int
+	result Result
+}
+
+// Solve searches the grid for up to limit solutions, where a limit below 1
+// counts every solution
+func Solve(shape sudoku.Shape, grid sudoku.Grid, limit int) Result {
+	return SolveKiller(shape, grid, nil, limit)
+}
+
+// SolveKiller is Solve for a Killer puzzle, where digits must also fit the cages
+func SolveKiller(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage, limit int) Result {
+	return SolveVariant(shape, grid, sudoku.Variant{Cages: cages}, limit)
+}
+
+// SolveVariant is Solve for a puzzle with cages and constraints
+func SolveVariant(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant, limit int) Result {
+	start := time.Now()
+
+	s := &search{
+		shape:  shape,
+		all:    uint16(shape.AllCandidates()),
+		grid:   sudoku.NewGrid(shape),
+		cageOf: make([]int, shape.NumCells()),
+		limit:  limit,
+	}
+
+	if shape.Validate() == nil && len(grid) == len(s.grid) && s.loadCages(variant.Cages) && s.loadConstraints(variant.Constraints) && s.load(grid) {
+		s.solve()
+	}
+
+	s.result.Stats.Elapsed = time.Since(start)
+
+	return s.result
+}
+
+func CountSolutions(shape sudoku.Shape, grid sudoku.Grid, limit int) int {
+	return Solve(shape, grid, limit).Count
+}
+
+func Unique(shape sudoku.Shape, grid sudoku.Grid) bool {
+	return CountSolutions(shape, grid, 2) == 1
+}
+
+func UniqueKiller(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage) bool {
+	return UniqueVariant(shape, grid, sudoku.Variant{Cages: cages})
+}
+
+func UniqueVariant(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant) bool {
+	return SolveVariant(shape, grid, variant, 2).Count == 1
+}
+
+// Solution returns the only solution of the grid, failing when it has none or several
+func Solution(shape sudoku.Shape, grid sudoku.Grid) (sudoku.Grid, error) {
+	return KillerSolution(shape, grid, nil)
+}
+
+func KillerSolution(shape sudoku.Shape, grid sudoku.Grid, cages []sudoku.Cage) (sudoku.Grid, error) {
+	return VariantSolution(shape, grid, sudoku.Variant{Cages: cages})
+}
+
+func VariantSolution(shape sudoku.Shape, grid sudoku.Grid, variant sudoku.Variant) (sudoku.Grid, error) {
+	result := SolveVariant(shape, grid, variant, 2)
+
+	switch result.Count {
+	case 0:
+		return nil, errors.New("puzzle has no solution")
+	case 1:
+		return result.Solution, nil
+	default:
+		return nil, errors.New("puzzle has multiple solutions")
+	}
+}
+
+// loadCages indexes the cages, reporting false if they are malformed
+func (s *search) loadCages(cages []sudoku.Cage) bool {
+	if s.shape.ValidateCages(cages) != nil {
+		return false
+	}
+
+	for idx := range s.cageOf {
+		s.cageOf[idx] = -1
+	}
+
+	for i, c := range cages {
+		s.cages = append(s.cages, cage{digits: s.all, sum: c.Sum, size: len(c.Cells)})
+
+		for _, idx := range c.Cells {
+			s.cageOf[idx] = i
+		}
+	}
+
+	return true
+}
+
+// loadConstraints indexes the extra houses, neighbors and lines of the
+// constraints, reporting false if one does not fit the shape
+func (s *search) loadConstraints(constraints sudoku.Constraints) bool {
+	if constraints.Validate(s.shape) != nil {
+		return false
+	}
+
+	s.houses = make([]uint16, s.shape.NumHouses())
+	s.houseOf = make([][]int, s.shape.NumCells())
+	s.links = make([][]link, s.shape.NumCells())
+	s.rules = make([][]rule, s.shape.NumCells())
+	s.digits = make([]byte, s.shape.NumCells())
+	s.open = make([]uint16, s.shape.NumCells())
+	s.houseCells = constraints.Houses(s.shape)
+
+	for idx := range s.houseOf {
+		s.houseOf[idx] = slices.Clone(s.shape.HousesOf(idx))
+	}
+
+	for house, cells := range s.houseCells[s.shape.NumHouses():] {
+		s.houses = append(s.houses, 0)
+
+		for _, idx := range cells {
+			s.houseOf[idx] = append(s.houseOf[idx], s.shape.NumHouses()+house)
+		}
+	}
+
+	for idx := range s.links {
+		for _, constraint := range constraints {
+			for _, neighbor := range constraint.Neighbors(s.shape, idx) {
+				l := link{cell: neighbor}
+
+				for digit := byte(1); int(digit) <= s.shape.Size(); digit++ {
+					l.excluded[digit] = uint16(constraint.Excluded(s.shape, idx, neighbor, digit))
+				}
+
+				s.links[idx] = append(s.links[idx], l)
+			}
+		}
+	}
+
+	for _, constraint := range constraints {
+		lines, ok := constraint.(sudoku.LineConstraint)
+
+		if !ok {
+			continue
+		}
+
+		for _, cells := range lines.Lines() {
+			for _, idx := range cells {
+				s.rules[idx] = append(s.rules[idx], rule{constraint: lines, cells: cells})
+			}
+		}
+	}
+
+	return true
+}
+
+// comboDigits[sum][cells][free] holds every digit of free that appears in
+// some set of cells distinct digits from free adding up to sum
+var comboDigits [sudoku.MaxCageDigits*(sudoku.MaxCageDigits+1)/2 + 1][sudoku.MaxCageDigits + 1][1 << sudoku.MaxCageDigits]uint16
+
+func init() {
+	for free := range 1 << sudoku.MaxCageDigits {
+		// Visit every subset of free, including the empty one
+		for subset := free; ; subset = (subset - 1) & free {
+			sum := 0
+
+			for rest := subset; rest != 0; rest &= rest - 1 {
+				sum += bits.TrailingZeros(uint(rest)) + 1
+			}
+
+			comboDigits[sum][bits.OnesCount(uint(subset))][free] |= uint16(subset)
+
+			if subset == 0 {
+				break
+			}
+		}
+	}
+}
+
+// open returns the digits that can still join a cage and leave room for its
+// remaining cells to reach the sum
+func (c *cage) open() uint16 {
+	left := c.sum - c.total
+	cells := c.size - c.filled
+
+	if left < 0 || left >= len(comboDigits) {
+		return 0
+	}
+
+	return comboDigits[left][cells][c.digits&^c.used]
+}
+
+// load places the givens, reporting false if any of them conflict
+func (s *search) load(grid sudoku.Grid) bool {
+	for idx, value := range grid {
+		if value == 0 {
+			continue
+		}
+
+		if int(value) > s.shape.Size() || !s.place(idx, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *search) place(idx int, digit byte) bool {
+	bit := uint16(1) << (digit - 1)
+
+	if s.candidates(idx)&bit == 0 {
+		return false
+	}
+
+	if c := s.cageOf[idx]; c >= 0 {
+		s.cages[c].used |= bit
+		s.cages[c].total += int(digit)
+		s.cages[c].filled++
+	}
+
+	s.grid[idx] = digit
+
+	for _, house := range s.houseOf[idx] {
+		s.houses[house] |= bit
+	}
+
+	return true
+}
+
+func (s *search) remove(idx int) {
+	bit := uint16(1) << (s.grid[idx] - 1)
+
+	if c := s.cageOf[idx]; c >= 0 {
+		s.cages[c].used &^= bit
+		s.cages[c].total -= int(s.grid[idx])
+		s.cages[c].filled--
+	}
+
+	s.grid[idx] = 0
+
+	for _, house := range s.houseOf[idx] {
+		s.houses[house] &^= bit
+	}
+}
+
+// lineDigits gathers the digits placed along a line, valid until the next call
+func (s *search) lineDigits(cells []int) []byte {
+	digits := s.digits[:len(cells)]
+
+	for i, idx := range cells {
+		digits[i] = s.grid[idx]
+	}
+
+	return digits
+}
+
+func (s *search) candidates(idx int) uint16 {
+	candidates := s.all
+
+	if c := s.cageOf[idx]; c >= 0 {
+		candidates &= s.cages[c].open()
+	}
+
+	for _, house := range s.houseOf[idx] {
+		candidates &^= s.houses[house]
+	}
+
+	for _, l := range s.links[idx] {
+		candidates &^= l.excluded[s.grid[l.cell]]
+	}
+
+	// Try each digit left against the lines through the cell, since a
+	// sum along an arrow reaches past the neighbors
+	for _, r := range s.rules[idx] {
+		for open := candidates; open != 0; open &= open - 1 {
+			s.grid[idx] = byte(bits.TrailingZeros16(open)) + 1
+
+			if !r.constraint.Allows(s.shape, s.lineDigits(r.cells)) {
+				candidates &^= open & -open
+			}
+		}
+
+		s.grid[idx] = 0
+	}
+
+	return candidates
+}
+
+// solve fills the most constrained empty cell first, or the one place left
+// for a digit in a house, and reports whether the search should stop
+// because the limit was reached
+func (s *search) solve() bool {
+	s.result.Stats.Nodes++
+
+	best := -1
+	bestCandidates := uint16(0)
+	bestCount := s.shape.Size() + 1
+
+	for idx, value := range s.grid {
+		if value != 0 {
+			continue
+		}
+
+		candidates := s.candidates(idx)
+		count := bits.OnesCount16(candidates)
+		s.open[idx] = candidates
+
+		if count == 0 {
+			s.result.Stats.Backtracks++
+			return false
+		}
+
+		if count < bestCount {
+			best, bestCandidates, bestCount = idx, candidates, count
+
+			if count == 1 {
+				break
+			}
+		}
+	}
+
+	if best < 0 {
+		if s.result.Count == 0 {
+			s.result.Solution = s.grid.Clone()
+		}
+
+		s.result.Count++
+
+		return s.limit > 0 && s.result.Count >= s.limit
+	}
+
+	if bestCount > 1 {
+		idx, bit, ok := s.hiddenSingle()
+
+		if !ok {
+			s.result.Stats.Backtracks++
+			return false
+		}
+
+		if idx >= 0 {
+			best, bestCandidates, bestCount = idx, bit, 1
+		}
+	}
+
+	if bestCount > 1 {
+		s.result.Stats.Guesses += bestCount - 1
+	}
+
+	for candidates := bestCandidates; candidates != 0; candidates &= candidates - 1 {
+		digit := byte(bits.TrailingZeros16(candidates)) + 1
+
+		s.place(best, digit)
+		done := s.solve()
+		s.remove(best)
+
+		if done {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hiddenSingle finds a digit with one place left in a house, given the
+// candidates of every empty cell in open. It returns -1 when there is none
+// and reports false when a digit has no place left at all.
+func (s *search) hiddenSingle() (int, uint16, bool) {
+	for house, cells := range s.houseCells {
+		if len(cells) != s.shape.Size() {
+			continue
+		}
+
+		seen, twice := s.houses[house], uint16(0)
+
+		for _, idx := range cells {
+			if s.grid[idx] == 0 {
+				twice |= seen & s.open[idx]
+				seen |= s.open[idx]
+			}
+		}
+
+		if seen != s.all {
+			return -1, 0, false
+		}
+
+		if once := seen &^ twice &^ s.houses[house]; once != 0 {
+			bit := once & -once
+
+			for _, idx := range cells {
+				if s.grid[idx] == 0 && s.open[idx]&bit != 0 {
+					return idx, bit, true
+				}
+			}
+		}
+	}
+
+	return -1, 0, true
+}
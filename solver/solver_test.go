@@ -0,0 +1,100 @@
+package solver
+
+import (
+	"testing"
+
+	"main/sudoku"
+)
+
+const (
+	classicPuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	classicSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+func parse(t *testing.T, shape sudoku.Shape, text string) sudoku.Grid {
+	t.Helper()
+
+	grid, err := sudoku.ParseGrid(shape, text)
+
+	if err != nil {
+		t.Fatalf("parsing grid: %s", err)
+	}
+
+	return grid
+}
+
+func TestSolution(t *testing.T) {
+	solution, err := Solution(sudoku.Classic, parse(t, sudoku.Classic, classicPuzzle))
+
+	if err != nil {
+		t.Fatalf("solving: %s", err)
+	}
+
+	if solution.String() != classicSolution {
+		t.Errorf("solution %s, want %s", solution, classicSolution)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	givens := parse(t, sudoku.Classic, classicPuzzle)
+
+	if !Unique(sudoku.Classic, givens) {
+		t.Errorf("puzzle with one solution is not unique")
+	}
+
+	// Dropping givens until several solutions open up
+	for idx := range givens {
+		givens[idx] = 0
+
+		if !Unique(sudoku.Classic, givens) {
+			break
+		}
+	}
+
+	if Unique(sudoku.Classic, givens) {
+		t.Errorf("grid with most givens removed is unique")
+	}
+
+	if count := CountSolutions(sudoku.Classic, givens, 2); count != 2 {
+		t.Errorf("counted %d solutions up to 2, want 2", count)
+	}
+}
+
+func TestNoSolution(t *testing.T) {
+	givens := parse(t, sudoku.Classic, classicPuzzle)
+
+	// A second 5 in the top row leaves the grid unsolvable
+	givens[2] = 5
+
+	if count := CountSolutions(sudoku.Classic, givens, 2); count != 0 {
+		t.Errorf("counted %d solutions of a broken grid", count)
+	}
+
+	if _, err := Solution(sudoku.Classic, givens); err == nil {
+		t.Errorf("broken grid was solved")
+	}
+}
+
+// TestShapes checks an empty grid of every shape fills in following the
+// rules of the shape
+func TestShapes(t *testing.T) {
+	for _, shape := range append(sudoku.Shapes(), sudoku.MultiShapes()...) {
+		result := Solve(shape, sudoku.NewGrid(shape), 1)
+
+		if result.Count != 1 {
+			t.Errorf("%s: found %d solutions of an empty grid", shape, result.Count)
+			continue
+		}
+
+		board, err := sudoku.FromGrid(shape, result.Solution)
+
+		if err != nil {
+			t.Errorf("%s: %s", shape, err)
+			continue
+		}
+
+		if !board.Solved() {
+			t.Errorf("%s: solution %s breaks the rules", shape, result.Solution)
+		}
+	}
+}
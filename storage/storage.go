@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const APP_NAME = "vysudoku"
+
+// DataDir returns the per-user directory the application keeps its files in
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, APP_NAME), nil
+	}
+
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		base, err := os.UserConfigDir()
+
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(base, APP_NAME), nil
+	default:
+		home, err := os.UserHomeDir()
+
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(home, ".local", "share", APP_NAME), nil
+	}
+}
+
+// Path returns the location of a file in the data directory
+func Path(name string) (string, error) {
+	dir, err := DataDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+func Exists(name string) bool {
+	path, err := Path(name)
+
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+
+	return err == nil
+}
+
+// WriteJSON stores v in the data directory, replacing the file in one step so
+// a crash never leaves it half written
+func WriteJSON(name string, v interface{}) error {
+	path, err := Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "\t")
+
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+
+	err = os.WriteFile(tmp, data, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func ReadJSON(name string, v interface{}) error {
+	path, err := Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Remove deletes a file from the data directory, ignoring files that do not exist
+func Remove(name string) error {
+	path, err := Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
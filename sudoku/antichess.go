@@ -0,0 +1,71 @@
+package sudoku
+
+import "fmt"
+
+var (
+	knightMoves = [][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+
+	// Orthogonal king moves stay in a row or column, so only the diagonal
+	// ones add anything
+	kingMoves = [][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+)
+
+// AntiKnight forbids a digit from repeating a chess knight's move away
+type AntiKnight struct{}
+
+func (AntiKnight) Name() string {
+	return "Anti-Knight"
+}
+
+func (AntiKnight) Validate(shape Shape) error {
+	return nil
+}
+
+func (AntiKnight) Houses(shape Shape) [][]int {
+	return nil
+}
+
+func (AntiKnight) Neighbors(shape Shape, idx int) []int {
+	return offsetNeighbors(shape, idx, knightMoves)
+}
+
+func (AntiKnight) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return CandidatesOf(digit)
+}
+
+func (AntiKnight) Overlay(shape Shape) Overlay {
+	return Overlay{}
+}
+
+// AntiKing forbids a digit from repeating a chess king's move away
+type AntiKing struct{}
+
+func (AntiKing) Name() string {
+	return "Anti-King"
+}
+
+// Validate rejects the 4x4 grid, which has no solution without a digit
+// touching itself diagonally
+func (AntiKing) Validate(shape Shape) error {
+	if shape.Size() <= 4 {
+		return fmt.Errorf("no %s grid can follow the rule", shape)
+	}
+
+	return nil
+}
+
+func (AntiKing) Houses(shape Shape) [][]int {
+	return nil
+}
+
+func (AntiKing) Neighbors(shape Shape, idx int) []int {
+	return offsetNeighbors(shape, idx, kingMoves)
+}
+
+func (AntiKing) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return CandidatesOf(digit)
+}
+
+func (AntiKing) Overlay(shape Shape) Overlay {
+	return Overlay{}
+}
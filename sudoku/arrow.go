@@ -0,0 +1,75 @@
+package sudoku
+
+// Arrow adds arrows, each with a circle at its first cell holding the sum of
+// the digits along the rest. Digits may repeat along an arrow where no house
+// forbids it.
+type Arrow struct {
+	lineSet
+}
+
+func (Arrow) Name() string {
+	return "Arrow"
+}
+
+func (Arrow) WithLines(lines [][]int) LineConstraint {
+	return Arrow{newLineSet(lines)}
+}
+
+func (a Arrow) Validate(shape Shape) error {
+	return a.validate(shape, 2, shape.Size())
+}
+
+// Neighbors pairs the circle with each cell of its arrow, which do not
+// restrict one another on their own
+func (a Arrow) Neighbors(shape Shape, idx int) []int {
+	line, pos := a.find(idx)
+
+	switch {
+	case line < 0:
+		return nil
+	case pos == 0:
+		return a.others(idx)
+	default:
+		return []int{a.lines[line][0]}
+	}
+}
+
+// Excluded keeps the circle above each digit of its arrow by at least one
+// for every other cell of the arrow, and each cell of the arrow below the
+// circle by as much
+func (a Arrow) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	line, pos := a.find(idx)
+	rest := len(a.lines[line]) - 2
+
+	if pos == 0 {
+		return CandidatesBetween(1, int(digit)+rest-1)
+	}
+
+	return CandidatesBetween(int(digit)-rest+1, shape.Size())
+}
+
+// Allows checks the circle against the smallest and largest sums the arrow
+// can still reach
+func (Arrow) Allows(shape Shape, digits []byte) bool {
+	sum, empty := 0, 0
+
+	for _, digit := range digits[1:] {
+		if digit == 0 {
+			empty++
+		} else {
+			sum += int(digit)
+		}
+	}
+
+	low, high := sum+empty, sum+empty*shape.Size()
+
+	if digits[0] == 0 {
+		return low <= shape.Size()
+	}
+
+	return low <= int(digits[0]) && int(digits[0]) <= high
+}
+
+func (a Arrow) Overlay(shape Shape) Overlay {
+	return Overlay{Lines: a.lines, Style: ArrowLine}
+}
@@ -0,0 +1,516 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Board tracks the givens of a puzzle, the player's entries and notes, and
+// the candidates left open for every empty cell
+type Board struct {
+	shape Shape
+
+	values     []byte
+	givens     []bool
+	candidates []Candidates
+	notes      []Candidates
+	colors     []byte
+
+	// cages are the Killer cages of the puzzle, with cageOf holding the
+	// index of the cage each cell is in or -1
+	cages  []Cage
+	cageOf []int
+
+	// constraints are the variant rules of the puzzle, and houses the
+	// houses of the shape followed by those the constraints add
+	constraints Constraints
+	houses      [][]int
+
+	// peerLists holds for every cell the cells that may not repeat its digit
+	peerLists [][]int
+}
+
+// CellState is everything the player can change about a cell
+type CellState struct {
+	Value byte       `json:"value"`
+	Notes Candidates `json:"notes"`
+	Color byte       `json:"color"`
+}
+
+// CheckState reports whether a cell of a grid of a shape with givens could
+// be in a state, as one read from a save must be before it is restored. A
+// given holds its digit and no notes, leaving only its color to change.
+func CheckState(shape Shape, givens Grid, idx int, state CellState) error {
+	if idx < 0 || idx >= len(givens) {
+		return fmt.Errorf("cell index out of range: %d", idx)
+	}
+
+	if givens[idx] != 0 && (state.Value != givens[idx] || state.Notes != 0) {
+		return fmt.Errorf("cell %d is a given", idx)
+	}
+
+	if int(state.Value) > shape.Size() {
+		return fmt.Errorf("invalid digit in cell %d: %d", idx, state.Value)
+	}
+
+	if state.Notes&^shape.AllCandidates() != 0 {
+		return fmt.Errorf("invalid notes in cell %d: %d", idx, state.Notes)
+	}
+
+	return nil
+}
+
+func NewBoard(shape Shape) *Board {
+	cells := shape.NumCells()
+	b := &Board{
+		shape:      shape,
+		values:     make([]byte, cells),
+		givens:     make([]bool, cells),
+		candidates: make([]Candidates, cells),
+		notes:      make([]Candidates, cells),
+		colors:     make([]byte, cells),
+		cageOf:     make([]int, cells),
+		houses:     shape.Houses(),
+	}
+
+	for idx := range cells {
+		b.candidates[idx] = shape.AllCandidates()
+		b.cageOf[idx] = -1
+	}
+
+	b.indexPeers()
+
+	return b
+}
+
+func FromGrid(shape Shape, givens Grid) (*Board, error) {
+	err := shape.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(givens) != shape.NumCells() {
+		return nil, fmt.Errorf("expected %d cells for a %s grid, got %d", shape.NumCells(), shape, len(givens))
+	}
+
+	b := NewBoard(shape)
+
+	for idx, value := range givens {
+		if int(value) > shape.Size() {
+			return nil, fmt.Errorf("invalid digit %d at cell %d", value, idx)
+		}
+
+		b.values[idx] = value
+		b.givens[idx] = value != 0
+	}
+
+	b.updateCandidates()
+
+	for idx, value := range givens {
+		if value == 0 {
+			continue
+		}
+
+		for _, peer := range shape.Peers(idx) {
+			if b.values[peer] == value {
+				row, col := shape.RowCol(idx)
+				return nil, fmt.Errorf("given %d at (%d, %d) repeats in a peer cell", value, row, col)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// SetCages turns the board into a Killer puzzle, failing if the cages are
+// malformed or the givens already break one
+func (b *Board) SetCages(cages []Cage) error {
+	err := b.shape.ValidateCages(cages)
+
+	if err != nil {
+		return err
+	}
+
+	for i, cage := range cages {
+		if cageBroken(cage, b.values) {
+			return fmt.Errorf("givens break cage %d summing to %d", i, cage.Sum)
+		}
+	}
+
+	b.cages = slices.Clone(cages)
+
+	for idx := range b.cageOf {
+		b.cageOf[idx] = -1
+	}
+
+	for i, cage := range b.cages {
+		for _, idx := range cage.Cells {
+			b.cageOf[idx] = i
+		}
+	}
+
+	b.indexPeers()
+	b.updateCandidates()
+
+	return nil
+}
+
+// SetConstraints adds variant rules to the board, failing if one does not
+// fit the shape or the givens already break one
+func (b *Board) SetConstraints(constraints Constraints) error {
+	err := constraints.Validate(b.shape)
+
+	if err != nil {
+		return err
+	}
+
+	previous := b.constraints
+	b.constraints = slices.Clone(constraints)
+	b.houses = b.constraints.Houses(b.shape)
+	b.indexPeers()
+
+	for idx, conflict := range b.Conflicts() {
+		if conflict && b.givens[idx] {
+			err := fmt.Errorf("given %d at %s breaks the constraints", b.values[idx], b.shape.CellName(idx))
+
+			b.constraints = previous
+			b.houses = b.constraints.Houses(b.shape)
+			b.indexPeers()
+
+			return err
+		}
+	}
+
+	b.updateCandidates()
+
+	return nil
+}
+
+func (b *Board) Shape() Shape {
+	return b.shape
+}
+
+func (b *Board) Cages() []Cage {
+	return b.cages
+}
+
+// CageOf returns the index of the cage holding a cell, or -1 if it has none
+func (b *Board) CageOf(idx int) int {
+	return b.cageOf[idx]
+}
+
+func (b *Board) Constraints() Constraints {
+	return b.constraints
+}
+
+// Variant returns the cages and constraints of the board
+func (b *Board) Variant() Variant {
+	return Variant{Cages: b.cages, Constraints: b.constraints}
+}
+
+// Houses returns the houses of the shape followed by those the constraints add
+func (b *Board) Houses() [][]int {
+	return b.houses
+}
+
+func (b *Board) HouseName(house int) string {
+	return b.constraints.HouseName(b.shape, house)
+}
+
+// indexPeers lists for every cell the cells that may not repeat its digit:
+// the other cells of its houses and of its cage
+func (b *Board) indexPeers() {
+	b.peerLists = make([][]int, len(b.values))
+
+	for idx := range b.peerLists {
+		b.peerLists[idx] = b.shape.Peers(idx)
+	}
+
+	groups := slices.Clone(b.houses[b.shape.NumHouses():])
+
+	for _, cage := range b.cages {
+		groups = append(groups, cage.Cells)
+	}
+
+	for _, group := range groups {
+		for _, idx := range group {
+			for _, cell := range group {
+				// Clip so the first extra peer copies the list shared with the shape
+				if cell != idx && !slices.Contains(b.peerLists[idx], cell) {
+					b.peerLists[idx] = append(slices.Clip(b.peerLists[idx]), cell)
+				}
+			}
+		}
+	}
+}
+
+func (b *Board) peers(idx int) []int {
+	return b.peerLists[idx]
+}
+
+func (b *Board) Value(idx int) byte {
+	return b.values[idx]
+}
+
+func (b *Board) Given(idx int) bool {
+	return b.givens[idx]
+}
+
+func (b *Board) Candidates(idx int) Candidates {
+	return b.candidates[idx]
+}
+
+// Notes returns the pencil marks the player made in a cell
+func (b *Board) Notes(idx int) Candidates {
+	return b.notes[idx]
+}
+
+func (b *Board) SetNotes(idx int, notes Candidates) error {
+	if idx < 0 || idx >= len(b.values) {
+		return fmt.Errorf("cell index out of range: %d", idx)
+	}
+
+	if b.givens[idx] {
+		return fmt.Errorf("cell %d is a given", idx)
+	}
+
+	b.notes[idx] = notes & b.shape.AllCandidates()
+
+	return nil
+}
+
+func (b *Board) ToggleNote(idx int, digit byte) error {
+	if digit < 1 || int(digit) > b.shape.Size() {
+		return fmt.Errorf("invalid digit: %d", digit)
+	}
+
+	if idx >= 0 && idx < len(b.values) && b.values[idx] != 0 {
+		return fmt.Errorf("cell %d already holds a digit", idx)
+	}
+
+	return b.SetNotes(idx, b.notes[idx].Toggle(digit))
+}
+
+// FillNotes sets the notes of every empty cell to its remaining candidates
+func (b *Board) FillNotes() {
+	for idx := range b.values {
+		if b.values[idx] == 0 {
+			b.notes[idx] = b.candidates[idx]
+		}
+	}
+}
+
+// Color returns the highlight the player marked a cell with, or 0 for none
+func (b *Board) Color(idx int) byte {
+	return b.colors[idx]
+}
+
+func (b *Board) SetColor(idx int, color byte) error {
+	if idx < 0 || idx >= len(b.values) {
+		return fmt.Errorf("cell index out of range: %d", idx)
+	}
+
+	b.colors[idx] = color
+
+	return nil
+}
+
+func (b *Board) State(idx int) CellState {
+	return CellState{
+		Value: b.values[idx],
+		Notes: b.notes[idx],
+		Color: b.colors[idx],
+	}
+}
+
+// Restore puts a cell back into a recorded state; givens keep their digit
+// and notes are kept to the digits of the grid
+func (b *Board) Restore(idx int, state CellState) {
+	if !b.givens[idx] {
+		b.values[idx] = state.Value
+		b.notes[idx] = state.Notes & b.shape.AllCandidates()
+	}
+
+	b.colors[idx] = state.Color
+	b.updatePeers(idx)
+}
+
+// RemovePeerNotes clears a digit from the notes of every peer of a cell,
+// along with the digits it rules out for the cell's neighbors
+func (b *Board) RemovePeerNotes(idx int, digit byte) {
+	for _, peer := range b.peers(idx) {
+		b.notes[peer] = b.notes[peer].Remove(digit)
+	}
+
+	for _, constraint := range b.constraints {
+		for _, neighbor := range constraint.Neighbors(b.shape, idx) {
+			b.notes[neighbor] &^= constraint.Excluded(b.shape, neighbor, idx, digit)
+		}
+	}
+}
+
+func (b *Board) Set(idx int, digit byte) error {
+	if idx < 0 || idx >= len(b.values) {
+		return fmt.Errorf("cell index out of range: %d", idx)
+	}
+
+	if digit < 1 || int(digit) > b.shape.Size() {
+		return fmt.Errorf("invalid digit: %d", digit)
+	}
+
+	if b.givens[idx] {
+		return fmt.Errorf("cell %d is a given", idx)
+	}
+
+	b.values[idx] = digit
+	b.updatePeers(idx)
+
+	return nil
+}
+
+func (b *Board) Erase(idx int) error {
+	if idx < 0 || idx >= len(b.values) {
+		return fmt.Errorf("cell index out of range: %d", idx)
+	}
+
+	if b.givens[idx] {
+		return fmt.Errorf("cell %d is a given", idx)
+	}
+
+	b.values[idx] = 0
+	b.updatePeers(idx)
+
+	return nil
+}
+
+// Reset erases every entry, note and color made by the player, keeping the givens
+func (b *Board) Reset() {
+	for idx := range b.values {
+		if !b.givens[idx] {
+			b.values[idx] = 0
+		}
+
+		b.notes[idx] = 0
+		b.colors[idx] = 0
+	}
+
+	b.updateCandidates()
+}
+
+func (b *Board) Grid() Grid {
+	return Grid(b.values).Clone()
+}
+
+func (b *Board) Givens() Grid {
+	grid := NewGrid(b.shape)
+
+	for idx := range grid {
+		if b.givens[idx] {
+			grid[idx] = b.values[idx]
+		}
+	}
+
+	return grid
+}
+
+func (b *Board) Filled() int {
+	return b.Grid().Clues()
+}
+
+// Complete reports whether every cell holds a digit, valid or not
+func (b *Board) Complete() bool {
+	return b.Filled() == len(b.values)
+}
+
+// Solved reports whether every cell holds a digit and none of them conflict,
+// so no house repeats a digit, every cage adds up and every constraint holds
+func (b *Board) Solved() bool {
+	return b.Complete() && !slices.Contains(b.Conflicts(), true)
+}
+
+// Conflicts marks every filled cell whose digit repeats in one of its peers
+// or is ruled out by a neighbor, along with the filled cells of any cage
+// whose sum is broken or line whose rule can no longer be followed
+func (b *Board) Conflicts() []bool {
+	conflicts := make([]bool, len(b.values))
+
+	for idx := range b.values {
+		if b.values[idx] == 0 {
+			continue
+		}
+
+		for _, peer := range b.peers(idx) {
+			if b.values[peer] == b.values[idx] {
+				conflicts[idx] = true
+				break
+			}
+		}
+
+		if b.constraints.Excluded(b.shape, b.values, idx).Has(b.values[idx]) {
+			conflicts[idx] = true
+		}
+	}
+
+	for _, cage := range b.cages {
+		if !cageBroken(cage, b.values) {
+			continue
+		}
+
+		for _, idx := range cage.Cells {
+			conflicts[idx] = conflicts[idx] || b.values[idx] != 0
+		}
+	}
+
+	for _, line := range b.constraints.BrokenLines(b.shape, b.values) {
+		for _, idx := range line {
+			conflicts[idx] = conflicts[idx] || b.values[idx] != 0
+		}
+	}
+
+	return conflicts
+}
+
+// Mistakes marks every player entry that differs from the solution
+func (b *Board) Mistakes(solution Grid) []bool {
+	mistakes := make([]bool, len(b.values))
+
+	for idx := range b.values {
+		mistakes[idx] = !b.givens[idx] && b.values[idx] != 0 && b.values[idx] != solution[idx]
+	}
+
+	return mistakes
+}
+
+func (b *Board) updateCandidates() {
+	for idx := range b.values {
+		b.updateCell(idx)
+	}
+}
+
+func (b *Board) updatePeers(idx int) {
+	b.updateCell(idx)
+
+	for _, peer := range b.peers(idx) {
+		b.updateCell(peer)
+	}
+
+	for _, neighbor := range b.constraints.Neighbors(b.shape, idx) {
+		b.updateCell(neighbor)
+	}
+}
+
+func (b *Board) updateCell(idx int) {
+	if b.values[idx] != 0 {
+		b.candidates[idx] = 0
+		return
+	}
+
+	candidates := b.shape.AllCandidates()
+
+	for _, peer := range b.peers(idx) {
+		candidates = candidates.Remove(b.values[peer])
+	}
+
+	b.candidates[idx] = candidates &^ b.constraints.Excluded(b.shape, b.values, idx)
+}
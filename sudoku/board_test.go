@@ -0,0 +1,278 @@
+package sudoku
+
+import (
+	"slices"
+	"testing"
+)
+
+const (
+	classicPuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	classicSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+func parse(t *testing.T, shape Shape, text string) Grid {
+	t.Helper()
+
+	grid, err := ParseGrid(shape, text)
+
+	if err != nil {
+		t.Fatalf("parsing grid: %s", err)
+	}
+
+	return grid
+}
+
+// board reads a classic grid into a board, failing the test if it is invalid
+func board(t *testing.T, text string) *Board {
+	t.Helper()
+
+	b, err := FromGrid(Classic, parse(t, Classic, text))
+
+	if err != nil {
+		t.Fatalf("reading board: %s", err)
+	}
+
+	return b
+}
+
+func TestFromGrid(t *testing.T) {
+	repeated := parse(t, Classic, classicPuzzle)
+	repeated[2] = 5
+
+	invalid := parse(t, Classic, classicPuzzle)
+	invalid[2] = 10
+
+	tests := []struct {
+		name  string
+		shape Shape
+		grid  Grid
+		ok    bool
+	}{
+		{"puzzle", Classic, parse(t, Classic, classicPuzzle), true},
+		{"empty", Classic, NewGrid(Classic), true},
+		{"small shape", Shape{BoxWidth: 2, BoxHeight: 2}, NewGrid(Shape{BoxWidth: 2, BoxHeight: 2}), true},
+		{"wrong length", Classic, NewGrid(Shape{BoxWidth: 2, BoxHeight: 2}), false},
+		{"invalid shape", Shape{BoxWidth: 5, BoxHeight: 5}, make(Grid, 625), false},
+		{"digit out of range", Classic, invalid, false},
+		{"repeated given", Classic, repeated, false},
+	}
+
+	for _, test := range tests {
+		_, err := FromGrid(test.shape, test.grid)
+
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: read without error", test.name)
+		}
+	}
+}
+
+func TestFromGridGivens(t *testing.T) {
+	givens := parse(t, Classic, classicPuzzle)
+	b := board(t, classicPuzzle)
+
+	for idx, value := range givens {
+		if b.Value(idx) != value {
+			t.Errorf("cell %d holds %d, want %d", idx, b.Value(idx), value)
+		}
+
+		if b.Given(idx) != (value != 0) {
+			t.Errorf("cell %d given is %t with value %d", idx, b.Given(idx), value)
+		}
+	}
+
+	if !slices.Equal(b.Givens(), givens) {
+		t.Errorf("givens %s, want %s", b.Givens(), givens)
+	}
+
+	// Cell 2 sees the 5, 3 and 7 of its row and the 6, 9 and 8 of its box
+	if b.Candidates(2).Has(5) || b.Candidates(2).Has(9) || !b.Candidates(2).Has(1) {
+		t.Errorf("cell 2 candidates %b", b.Candidates(2))
+	}
+}
+
+func TestSet(t *testing.T) {
+	b := board(t, classicPuzzle)
+
+	tests := []struct {
+		name  string
+		idx   int
+		digit byte
+		ok    bool
+	}{
+		{"empty cell", 2, 4, true},
+		{"given", 0, 4, false},
+		{"zero digit", 2, 0, false},
+		{"digit out of range", 2, 10, false},
+		{"negative index", -1, 4, false},
+		{"index out of range", 81, 4, false},
+	}
+
+	for _, test := range tests {
+		err := b.Set(test.idx, test.digit)
+
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: set without error", test.name)
+		}
+	}
+
+	if b.Value(2) != 4 || b.Given(2) {
+		t.Errorf("cell 2 holds %d, given %t after setting 4", b.Value(2), b.Given(2))
+	}
+
+	if b.Value(0) != 5 {
+		t.Errorf("given changed to %d", b.Value(0))
+	}
+
+	// The 4 is no longer open to the rest of the row
+	if b.Candidates(5).Has(4) {
+		t.Errorf("peer of a placed 4 keeps it as a candidate")
+	}
+
+	if err := b.Erase(2); err != nil {
+		t.Fatalf("erasing: %s", err)
+	}
+
+	if b.Value(2) != 0 || !b.Candidates(5).Has(4) {
+		t.Errorf("erasing left %d in cell 2", b.Value(2))
+	}
+
+	if err := b.Erase(0); err == nil {
+		t.Errorf("erased a given")
+	}
+}
+
+func TestPeers(t *testing.T) {
+	b := NewBoard(Classic)
+
+	for idx := range Classic.NumCells() {
+		peers := b.peers(idx)
+
+		if len(peers) != 20 {
+			t.Errorf("cell %d has %d peers, want 20", idx, len(peers))
+		}
+
+		if slices.Contains(peers, idx) {
+			t.Errorf("cell %d is its own peer", idx)
+		}
+	}
+
+	// The top left cell sees along its row, down its column and across its box
+	for _, peer := range []int{1, 8, 9, 10, 20, 72} {
+		if !slices.Contains(b.peers(0), peer) {
+			t.Errorf("cell %d is not a peer of cell 0", peer)
+		}
+	}
+
+	for _, other := range []int{12, 30, 80} {
+		if slices.Contains(b.peers(0), other) {
+			t.Errorf("cell %d is a peer of cell 0", other)
+		}
+	}
+}
+
+func TestCompleteSolved(t *testing.T) {
+	solved := board(t, classicSolution)
+
+	if !solved.Complete() || !solved.Solved() {
+		t.Errorf("solution complete %t, solved %t", solved.Complete(), solved.Solved())
+	}
+
+	b := board(t, classicPuzzle)
+	solution := parse(t, Classic, classicSolution)
+
+	for idx, value := range solution {
+		if !b.Given(idx) {
+			b.Set(idx, value)
+		}
+	}
+
+	if !b.Solved() {
+		t.Errorf("puzzle filled with its solution is not solved")
+	}
+
+	// Swapping the entries of two cells of a row keeps the board full but
+	// repeats both digits in their columns
+	b.Set(2, solution[3])
+	b.Set(3, solution[2])
+
+	if !b.Complete() || b.Solved() {
+		t.Errorf("swapped board complete %t, solved %t", b.Complete(), b.Solved())
+	}
+
+	b.Erase(2)
+
+	if b.Complete() {
+		t.Errorf("board with an empty cell is complete")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	b := board(t, classicPuzzle)
+
+	if slices.Contains(b.Conflicts(), true) {
+		t.Fatalf("puzzle has conflicts before any entry")
+	}
+
+	// A second 5 in the top row clashes with the given in cell 0
+	b.Set(2, 5)
+
+	conflicts := b.Conflicts()
+
+	for idx, want := range map[int]bool{0: true, 2: true, 19: false} {
+		if conflicts[idx] != want {
+			t.Errorf("cell %d conflict %t, want %t", idx, conflicts[idx], want)
+		}
+	}
+
+	// A 9 instead clashes with the given in cell 19 of the same box
+	b.Set(2, 9)
+	conflicts = b.Conflicts()
+
+	for idx, want := range map[int]bool{0: false, 2: true, 19: true} {
+		if conflicts[idx] != want {
+			t.Errorf("cell %d conflict %t, want %t", idx, conflicts[idx], want)
+		}
+	}
+}
+
+func TestCheckState(t *testing.T) {
+	givens := parse(t, Classic, classicPuzzle)
+
+	tests := []struct {
+		name  string
+		idx   int
+		state CellState
+		ok    bool
+	}{
+		{"empty cell", 2, CellState{}, true},
+		{"entry with notes and color", 2, CellState{Value: 4, Notes: CandidatesOf(1, 9), Color: 2}, true},
+		{"given", 0, CellState{Value: 5, Color: 1}, true},
+		{"given changed", 0, CellState{Value: 4}, false},
+		{"given erased", 0, CellState{}, false},
+		{"notes on a given", 0, CellState{Value: 5, Notes: CandidatesOf(1)}, false},
+		{"digit out of range", 2, CellState{Value: 10}, false},
+		{"notes out of range", 2, CellState{Notes: Classic.AllCandidates() << 1}, false},
+		{"negative index", -1, CellState{}, false},
+		{"index out of range", 81, CellState{}, false},
+	}
+
+	for _, test := range tests {
+		err := CheckState(Classic, givens, test.idx, test.state)
+
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: checked without error", test.name)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Cage is a Killer Sudoku region whose digits may not repeat and must add
+// up to Sum
+type Cage struct {
+	Cells []int `json:"cells"`
+	Sum   int   `json:"sum"`
+}
+
+// MaxCageDigits is the most digits a grid with cages can use
+const MaxCageDigits = 9
+
+// SumRange returns the smallest and largest sums a cage of size cells can
+// reach with distinct digits of the shape
+func (s Shape) SumRange(size int) (int, int) {
+	return size * (size + 1) / 2, size * (2*s.Size() - size + 1) / 2
+}
+
+// Combinations lists every set of size distinct digits of the shape adding up to sum
+func (s Shape) Combinations(size int, sum int) []Candidates {
+	combos := []Candidates{}
+
+	var pick func(next byte, left int, total int, set Candidates)
+
+	pick = func(next byte, left int, total int, set Candidates) {
+		if left == 0 {
+			if total == sum {
+				combos = append(combos, set)
+			}
+
+			return
+		}
+
+		for digit := next; int(digit) <= s.Size() && total+int(digit) <= sum; digit++ {
+			pick(digit+1, left-1, total+int(digit), set.Add(digit))
+		}
+	}
+
+	pick(1, size, 0, 0)
+
+	return combos
+}
+
+func (c Cage) Contains(idx int) bool {
+	return slices.Contains(c.Cells, idx)
+}
+
+// ValidateCages checks that every cage holds between one cell and a house's
+// worth inside the grid, that no cell belongs to two cages and that every
+// sum is reachable
+func (s Shape) ValidateCages(cages []Cage) error {
+	if len(cages) > 0 && s.Size() > MaxCageDigits {
+		return fmt.Errorf("cages need a grid of at most %d digits", MaxCageDigits)
+	}
+
+	if len(cages) > 0 && s.Multi() {
+		return fmt.Errorf("the %s layout cannot have cages", s)
+	}
+
+	seen := make([]bool, s.NumCells())
+
+	for i, cage := range cages {
+		if len(cage.Cells) < 1 || len(cage.Cells) > s.Size() {
+			return fmt.Errorf("cage %d has %d cells", i, len(cage.Cells))
+		}
+
+		for _, idx := range cage.Cells {
+			if idx < 0 || idx >= len(seen) {
+				return fmt.Errorf("cage %d has cell index out of range: %d", i, idx)
+			}
+
+			if seen[idx] {
+				return fmt.Errorf("cell %s is in more than one cage", s.CellName(idx))
+			}
+
+			seen[idx] = true
+		}
+
+		low, high := s.SumRange(len(cage.Cells))
+
+		if cage.Sum < low || cage.Sum > high {
+			return fmt.Errorf("cage %d of %d cells cannot sum to %d", i, len(cage.Cells), cage.Sum)
+		}
+	}
+
+	return nil
+}
+
+// cageBroken reports whether the digits placed in a cage repeat, overshoot
+// its sum or fill it with the wrong total
+func cageBroken(cage Cage, values []byte) bool {
+	seen := Candidates(0)
+	total := 0
+	filled := 0
+
+	for _, idx := range cage.Cells {
+		value := values[idx]
+
+		if value == 0 {
+			continue
+		}
+
+		if seen.Has(value) {
+			return true
+		}
+
+		seen = seen.Add(value)
+		total += int(value)
+		filled++
+	}
+
+	if filled == len(cage.Cells) {
+		return total != cage.Sum
+	}
+
+	return total >= cage.Sum
+}
@@ -0,0 +1,71 @@
+package sudoku
+
+import "testing"
+
+func TestValidateCages(t *testing.T) {
+	// Runs of three cells covering the grid, each summing to 6
+	rows := []Cage{}
+
+	for idx := 0; idx < Classic.NumCells(); idx += 3 {
+		rows = append(rows, Cage{Cells: []int{idx, idx + 1, idx + 2}, Sum: 6})
+	}
+
+	tests := []struct {
+		name  string
+		shape Shape
+		cages []Cage
+		ok    bool
+	}{
+		{"no cages", Classic, nil, true},
+		{"every cell covered", Classic, rows, true},
+		{"cells outside every cage", Classic, []Cage{{Cells: []int{0, 1}, Sum: 3}}, true},
+		{"single cell", Classic, []Cage{{Cells: []int{0}, Sum: 9}}, true},
+		{"overlapping", Classic, []Cage{{Cells: []int{0, 1}, Sum: 3}, {Cells: []int{1, 2}, Sum: 3}}, false},
+		{"cell repeated in a cage", Classic, []Cage{{Cells: []int{0, 0}, Sum: 3}}, false},
+		{"empty", Classic, []Cage{{Cells: []int{}, Sum: 0}}, false},
+		{"larger than a house", Classic, []Cage{{Cells: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, Sum: 45}}, false},
+		{"cell out of range", Classic, []Cage{{Cells: []int{80, 81}, Sum: 3}}, false},
+		{"negative cell", Classic, []Cage{{Cells: []int{-1, 0}, Sum: 3}}, false},
+		{"sum too low", Classic, []Cage{{Cells: []int{0, 1}, Sum: 2}}, false},
+		{"sum too high", Classic, []Cage{{Cells: []int{0, 1}, Sum: 18}}, false},
+		{"highest sum", Classic, []Cage{{Cells: []int{0, 1}, Sum: 17}}, true},
+		{"too many digits", Shape{BoxWidth: 4, BoxHeight: 4}, []Cage{{Cells: []int{0, 1}, Sum: 3}}, false},
+		{"multi layout", MultiShapes()[0], []Cage{{Cells: []int{0, 1}, Sum: 3}}, false},
+	}
+
+	for _, test := range tests {
+		err := test.shape.ValidateCages(test.cages)
+
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: validated without error", test.name)
+		}
+	}
+}
+
+func TestCageBroken(t *testing.T) {
+	cage := Cage{Cells: []int{0, 1, 2}, Sum: 10}
+
+	tests := []struct {
+		name   string
+		values []byte
+		broken bool
+	}{
+		{"empty", []byte{0, 0, 0}, false},
+		{"partly filled under the sum", []byte{1, 2, 0}, false},
+		{"filled to the sum", []byte{1, 2, 7}, false},
+		{"filled to another sum", []byte{1, 2, 6}, true},
+		{"partly filled reaching the sum", []byte{3, 7, 0}, true},
+		{"partly filled over the sum", []byte{4, 8, 0}, true},
+		{"repeated digit", []byte{2, 2, 0}, true},
+	}
+
+	for _, test := range tests {
+		if broken := cageBroken(cage, test.values); broken != test.broken {
+			t.Errorf("%s: broken %t, want %t", test.name, broken, test.broken)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package sudoku
+
+import "math/bits"
+
+// Candidates is a set of digits, with digit d stored in bit d-1
+type Candidates uint16
+
+func CandidatesOf(digits ...byte) Candidates {
+	c := Candidates(0)
+
+	for _, digit := range digits {
+		c = c.Add(digit)
+	}
+
+	return c
+}
+
+// CandidatesBetween holds the digits from low to high, leaving out any
+// outside 1 to MaxSize
+func CandidatesBetween(low int, high int) Candidates {
+	low, high = max(low, 1), min(high, MaxSize)
+
+	if low > high {
+		return 0
+	}
+
+	return Candidates(1<<high - 1<<(low-1))
+}
+
+func (c Candidates) Has(digit byte) bool {
+	return digit >= 1 && digit <= MaxSize && c&(1<<(digit-1)) != 0
+}
+
+func (c Candidates) Add(digit byte) Candidates {
+	if digit < 1 || digit > MaxSize {
+		return c
+	}
+
+	return c | 1<<(digit-1)
+}
+
+func (c Candidates) Remove(digit byte) Candidates {
+	if digit < 1 || digit > MaxSize {
+		return c
+	}
+
+	return c &^ (1 << (digit - 1))
+}
+
+func (c Candidates) Toggle(digit byte) Candidates {
+	if c.Has(digit) {
+		return c.Remove(digit)
+	}
+
+	return c.Add(digit)
+}
+
+func (c Candidates) Count() int {
+	return bits.OnesCount16(uint16(c))
+}
+
+func (c Candidates) Empty() bool {
+	return c == 0
+}
+
+// Single returns the only digit in the set, if there is exactly one
+func (c Candidates) Single() (byte, bool) {
+	if c.Count() != 1 {
+		return 0, false
+	}
+
+	return byte(bits.TrailingZeros16(uint16(c))) + 1, true
+}
+
+func (c Candidates) Digits() []byte {
+	digits := []byte{}
+
+	for digit := byte(1); digit <= MaxSize; digit++ {
+		if c.Has(digit) {
+			digits = append(digits, digit)
+		}
+	}
+
+	return digits
+}
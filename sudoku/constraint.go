@@ -0,0 +1,280 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Constraint is a rule layered over the rows, columns and boxes of a grid.
+// It restricts digits in two ways: through extra houses that may not repeat
+// a digit, and through neighbors whose digits rule out digits of a cell.
+type Constraint interface {
+	// Name identifies the constraint in saved games and on screen
+	Name() string
+
+	// Validate reports whether the constraint fits a shape
+	Validate(shape Shape) error
+
+	// Houses returns extra groups of cells that hold every digit once, so
+	// each has as many cells as the shape has digits
+	Houses(shape Shape) [][]int
+
+	// Neighbors returns the cells whose digits restrict a cell beyond its
+	// houses. The relation is symmetric: every neighbor lists the cell back.
+	Neighbors(shape Shape, idx int) []int
+
+	// Excluded returns the digits a cell may not hold while its neighbor
+	// holds digit
+	Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates
+
+	// Overlay describes the marks the constraint adds to the drawn grid
+	Overlay(shape Shape) Overlay
+}
+
+// Overlay describes how a constraint is drawn, leaving the colors and line
+// widths to the interface
+type Overlay struct {
+	// Shaded cells are tinted to pick out extra houses
+	Shaded []int
+
+	// Lines each run through the centers of their cells in order, drawn
+	// in the style of the constraint
+	Lines [][]int
+	Style LineStyle
+
+	// Edges are the markers drawn in the gaps between cells
+	Edges []Edge
+}
+
+// Constraints is the set of rules a puzzle adds to the classic ones
+type Constraints []Constraint
+
+// AllConstraints lists every constraint in the order the interface offers them
+func AllConstraints() Constraints {
+	return Constraints{Diagonal{}, Windoku{}, AntiKnight{}, AntiKing{}, NonConsecutive{}}
+}
+
+// ConstraintByName finds a constraint by name, returning line and edge
+// constraints without any lines or edges
+func ConstraintByName(name string) (Constraint, error) {
+	for _, constraint := range AllConstraints() {
+		if strings.EqualFold(constraint.Name(), name) {
+			return constraint, nil
+		}
+	}
+
+	for _, constraint := range AllLineConstraints() {
+		if strings.EqualFold(constraint.Name(), name) {
+			return constraint, nil
+		}
+	}
+
+	for _, constraint := range AllEdgeConstraints() {
+		if strings.EqualFold(constraint.Name(), name) {
+			return constraint, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown constraint: %s", name)
+}
+
+// ConstraintSpec is the stored form of a constraint: its name and, for a
+// line constraint, the cells of each line, or for an edge constraint its
+// edges and whether it is negative
+type ConstraintSpec struct {
+	Name  string  `json:"name"`
+	Lines [][]int `json:"lines,omitempty"`
+
+	Edges    []Edge `json:"edges,omitempty"`
+	Negative bool   `json:"negative,omitempty"`
+}
+
+// UnmarshalJSON also reads a bare name, as constraints were stored before
+// they could carry lines
+func (s *ConstraintSpec) UnmarshalJSON(data []byte) error {
+	name := ""
+
+	if json.Unmarshal(data, &name) == nil {
+		*s = ConstraintSpec{Name: name}
+		return nil
+	}
+
+	type plain ConstraintSpec
+
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+func ParseConstraints(specs []ConstraintSpec) (Constraints, error) {
+	constraints := Constraints{}
+
+	for _, spec := range specs {
+		constraint, err := ConstraintByName(spec.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(spec.Lines) > 0 {
+			lines, ok := constraint.(LineConstraint)
+
+			if !ok {
+				return nil, fmt.Errorf("constraint %s has no lines", constraint.Name())
+			}
+
+			constraint = lines.WithLines(spec.Lines)
+		}
+
+		if len(spec.Edges) > 0 || spec.Negative {
+			edges, ok := constraint.(EdgeConstraint)
+
+			if !ok {
+				return nil, fmt.Errorf("constraint %s has no edges", constraint.Name())
+			}
+
+			constraint = edges.WithEdges(spec.Edges, spec.Negative)
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
+// Specs returns the stored form of each constraint
+func (c Constraints) Specs() []ConstraintSpec {
+	specs := []ConstraintSpec{}
+
+	for _, constraint := range c {
+		spec := ConstraintSpec{Name: constraint.Name()}
+
+		if lines, ok := constraint.(LineConstraint); ok {
+			spec.Lines = lines.Lines()
+		}
+
+		if edges, ok := constraint.(EdgeConstraint); ok {
+			spec.Edges = edges.Edges()
+			spec.Negative = edges.Negative()
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+func (c Constraints) Names() []string {
+	names := []string{}
+
+	for _, constraint := range c {
+		names = append(names, constraint.Name())
+	}
+
+	return names
+}
+
+func (c Constraints) String() string {
+	return strings.Join(c.Names(), " ")
+}
+
+// Validate checks that every constraint fits the shape and none is repeated
+func (c Constraints) Validate(shape Shape) error {
+	if shape.Multi() && len(c) > 0 {
+		return fmt.Errorf("the %s layout cannot have constraints", shape)
+	}
+
+	seen := map[string]bool{}
+
+	for _, constraint := range c {
+		if seen[constraint.Name()] {
+			return fmt.Errorf("constraint %s is given twice", constraint.Name())
+		}
+
+		seen[constraint.Name()] = true
+
+		err := constraint.Validate(shape)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", constraint.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Houses returns the houses of the shape followed by those of each
+// constraint in turn, so the first houses are numbered as in Shape.Houses
+func (c Constraints) Houses(shape Shape) [][]int {
+	houses := append([][]int{}, shape.Houses()...)
+
+	for _, constraint := range c {
+		houses = append(houses, constraint.Houses(shape)...)
+	}
+
+	return houses
+}
+
+// HouseName names a house numbered as in Houses
+func (c Constraints) HouseName(shape Shape, house int) string {
+	if house < shape.NumHouses() {
+		return shape.HouseName(house)
+	}
+
+	house -= shape.NumHouses()
+
+	for _, constraint := range c {
+		count := len(constraint.Houses(shape))
+
+		if house < count {
+			return fmt.Sprintf("%s %d", strings.ToLower(constraint.Name()), house+1)
+		}
+
+		house -= count
+	}
+
+	return fmt.Sprintf("house %d", house+1)
+}
+
+// Excluded returns the digits the neighbors of a cell rule out given the
+// values placed in the grid
+func (c Constraints) Excluded(shape Shape, values []byte, idx int) Candidates {
+	excluded := Candidates(0)
+
+	for _, constraint := range c {
+		for _, neighbor := range constraint.Neighbors(shape, idx) {
+			if values[neighbor] != 0 {
+				excluded |= constraint.Excluded(shape, idx, neighbor, values[neighbor])
+			}
+		}
+	}
+
+	return excluded
+}
+
+// Neighbors returns the neighbors of a cell under any of the constraints
+func (c Constraints) Neighbors(shape Shape, idx int) []int {
+	neighbors := []int{}
+
+	for _, constraint := range c {
+		neighbors = append(neighbors, constraint.Neighbors(shape, idx)...)
+	}
+
+	return neighbors
+}
+
+// offsetNeighbors returns the cells a fixed set of row and column steps away
+// from a cell that fall inside the grid
+func offsetNeighbors(shape Shape, idx int, offsets [][2]int) []int {
+	row, col := shape.RowCol(idx)
+	neighbors := []int{}
+
+	for _, offset := range offsets {
+		r, c := row+offset[0], col+offset[1]
+
+		if r >= 0 && r < shape.Size() && c >= 0 && c < shape.Size() {
+			neighbors = append(neighbors, shape.Index(r, c))
+		}
+	}
+
+	return neighbors
+}
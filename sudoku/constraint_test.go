@@ -0,0 +1,118 @@
+package sudoku
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []ConstraintSpec
+		want  []string
+		ok    bool
+	}{
+		{"none", nil, []string{}, true},
+		{"plain", []ConstraintSpec{{Name: "Diagonal"}, {Name: "Anti-King"}}, []string{"Diagonal", "Anti-King"}, true},
+		{"any case", []ConstraintSpec{{Name: "windoku"}}, []string{"Windoku"}, true},
+		{"line", []ConstraintSpec{{Name: "Thermo", Lines: [][]int{{0, 1, 2}}}}, []string{"Thermo"}, true},
+		{"edge", []ConstraintSpec{{Name: "XV", Edges: []Edge{{Cells: [2]int{0, 1}, Marker: XMark}}}}, []string{"XV"}, true},
+		{"negative edge", []ConstraintSpec{{Name: "XV", Negative: true}}, []string{"XV"}, true},
+		{"unknown", []ConstraintSpec{{Name: "Sandwich"}}, nil, false},
+		{"lines on a plain constraint", []ConstraintSpec{{Name: "Diagonal", Lines: [][]int{{0, 1}}}}, nil, false},
+		{"edges on a line constraint", []ConstraintSpec{{Name: "Thermo", Edges: []Edge{{Cells: [2]int{0, 1}}}}}, nil, false},
+		{"negative plain constraint", []ConstraintSpec{{Name: "Diagonal", Negative: true}}, nil, false},
+	}
+
+	for _, test := range tests {
+		constraints, err := ParseConstraints(test.specs)
+
+		if !test.ok {
+			if err == nil {
+				t.Errorf("%s: parsed without error", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		names := []string{}
+
+		for _, constraint := range constraints {
+			names = append(names, constraint.Name())
+		}
+
+		if !slices.Equal(names, test.want) {
+			t.Errorf("%s: parsed %v, want %v", test.name, names, test.want)
+		}
+	}
+}
+
+// TestParseConstraintsSpecs checks constraints read back from their stored
+// form keep their lines, edges and negation
+func TestParseConstraintsSpecs(t *testing.T) {
+	constraints := Constraints{
+		Diagonal{},
+		Thermo{}.WithLines([][]int{{0, 1, 2}, {9, 10}}),
+		XV{}.WithEdges([]Edge{{Cells: [2]int{0, 9}, Marker: VMark}}, true),
+	}
+
+	parsed, err := ParseConstraints(constraints.Specs())
+
+	if err != nil {
+		t.Fatalf("parsing specs: %s", err)
+	}
+
+	for i, spec := range parsed.Specs() {
+		want := constraints.Specs()[i]
+
+		if spec.Name != want.Name || len(spec.Lines) != len(want.Lines) || len(spec.Edges) != len(want.Edges) || spec.Negative != want.Negative {
+			t.Errorf("spec %d read back as %+v, want %+v", i, spec, want)
+		}
+	}
+}
+
+// TestSetConstraintsRollback checks constraints the givens break are
+// refused, leaving the board with the constraints it had
+func TestSetConstraintsRollback(t *testing.T) {
+	// A 1 in the top left corner and the center share the long diagonal
+	text := []byte(strings.Repeat(".", Classic.NumCells()))
+	text[0], text[40] = '1', '1'
+
+	b := board(t, string(text))
+
+	if err := b.SetConstraints(Constraints{AntiKing{}}); err != nil {
+		t.Fatalf("setting constraints the givens keep: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		constraints Constraints
+	}{
+		{"broken by givens", Constraints{Diagonal{}}},
+		{"invalid", Constraints{Thermo{}.WithLines([][]int{{0, 81}})}},
+	}
+
+	for _, test := range tests {
+		if err := b.SetConstraints(test.constraints); err == nil {
+			t.Errorf("%s: set without error", test.name)
+		}
+
+		if names := b.Constraints().Specs(); len(names) != 1 || names[0].Name != "Anti-King" {
+			t.Errorf("%s: board left with %v", test.name, names)
+		}
+
+		if slices.Contains(b.peers(0), 40) {
+			t.Errorf("%s: diagonal cells stayed peers", test.name)
+		}
+
+		if !b.Candidates(80).Has(1) {
+			t.Errorf("%s: the far corner lost 1 as a candidate", test.name)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package sudoku
+
+// Diagonal is X-Sudoku: both long diagonals hold every digit once
+type Diagonal struct{}
+
+func (Diagonal) Name() string {
+	return "Diagonal"
+}
+
+func (Diagonal) Validate(shape Shape) error {
+	return nil
+}
+
+// Houses returns the diagonal from the top left, then the one from the top right
+func (Diagonal) Houses(shape Shape) [][]int {
+	down := []int{}
+	up := []int{}
+
+	for i := range shape.Size() {
+		down = append(down, shape.Index(i, i))
+		up = append(up, shape.Index(i, shape.Size()-1-i))
+	}
+
+	return [][]int{down, up}
+}
+
+func (Diagonal) Neighbors(shape Shape, idx int) []int {
+	return nil
+}
+
+func (Diagonal) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return 0
+}
+
+func (d Diagonal) Overlay(shape Shape) Overlay {
+	return Overlay{Lines: d.Houses(shape)}
+}
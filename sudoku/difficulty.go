@@ -0,0 +1,40 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+	Extreme
+)
+
+var difficultyNames = [...]string{"Easy", "Medium", "Hard", "Expert", "Extreme"}
+
+func Difficulties() []Difficulty {
+	return []Difficulty{Easy, Medium, Hard, Expert, Extreme}
+}
+
+func (d Difficulty) String() string {
+	if d < Easy || d > Extreme {
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+
+	return difficultyNames[d]
+}
+
+func ParseDifficulty(name string) (Difficulty, error) {
+	for i, difficultyName := range difficultyNames {
+		if strings.EqualFold(name, difficultyName) {
+			return Difficulty(i), nil
+		}
+	}
+
+	return Easy, fmt.Errorf("unknown difficulty: %s", name)
+}
@@ -0,0 +1,206 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EdgeConstraint is a constraint drawn as markers on the sides between
+// cells, each tying the digits on either side of it. Under the negative
+// constraint every pair of cells side by side that could take a marker is
+// given one, so an unmarked side rules out what the markers stand for.
+type EdgeConstraint interface {
+	Constraint
+
+	// Edges returns the marked sides
+	Edges() []Edge
+
+	// Negative reports whether unmarked sides carry the negative constraint
+	Negative() bool
+
+	// WithEdges returns the same kind of constraint over other edges
+	WithEdges(edges []Edge, negative bool) EdgeConstraint
+
+	// Markers lists the markers the constraint draws
+	Markers() []Marker
+
+	// Negatable reports whether the constraint can be negative, which needs
+	// markers that read the same either way round
+	Negatable() bool
+}
+
+// AllEdgeConstraints lists every kind of edge constraint, each without edges,
+// in the order the interface offers them
+func AllEdgeConstraints() []EdgeConstraint {
+	return []EdgeConstraint{Kropki{}, XV{}, GreaterThan{}}
+}
+
+// Marker is the mark on an edge, which decides the rule it puts on the
+// digits either side
+type Marker int
+
+const (
+	// WhiteDot joins consecutive digits
+	WhiteDot Marker = iota
+	// BlackDot joins digits one of which is double the other
+	BlackDot
+	// XMark joins digits adding up to 10
+	XMark
+	// VMark joins digits adding up to 5
+	VMark
+	// GreaterMark points from the larger digit to the smaller
+	GreaterMark
+)
+
+// Allows reports whether digits a and b on the first and second cell of an
+// edge follow the marker
+func (m Marker) Allows(a byte, b byte) bool {
+	switch m {
+	case WhiteDot:
+		return a+1 == b || b+1 == a
+	case BlackDot:
+		return a == 2*b || b == 2*a
+	case XMark:
+		return a+b == 10
+	case VMark:
+		return a+b == 5
+	case GreaterMark:
+		return a > b
+	default:
+		return false
+	}
+}
+
+// Edge is a marker between two cells sharing a side. A GreaterMark points
+// from the first cell, which holds the larger digit.
+type Edge struct {
+	Cells  [2]int `json:"cells"`
+	Marker Marker `json:"marker"`
+}
+
+// edgeSet holds the edges of an edge constraint, at most one on each side,
+// with index finding the edge between a pair of cells
+type edgeSet struct {
+	edges    []Edge
+	negative bool
+	index    map[[2]int]int
+}
+
+func newEdgeSet(edges []Edge, negative bool) edgeSet {
+	set := edgeSet{edges: slices.Clone(edges), negative: negative, index: map[[2]int]int{}}
+
+	for i, edge := range set.edges {
+		set.index[edge.Cells] = i
+		set.index[[2]int{edge.Cells[1], edge.Cells[0]}] = i
+	}
+
+	return set
+}
+
+func (e edgeSet) Edges() []Edge {
+	return e.edges
+}
+
+func (e edgeSet) Negative() bool {
+	return e.negative
+}
+
+func (e edgeSet) Houses(shape Shape) [][]int {
+	return nil
+}
+
+func (e edgeSet) Overlay(shape Shape) Overlay {
+	return Overlay{Edges: e.edges}
+}
+
+// validate checks that each edge joins two cells sharing a side with one of
+// the markers, that no side has two, and that only a negatable constraint
+// is negative
+func (e edgeSet) validate(shape Shape, markers []Marker, negatable bool) error {
+	if e.negative && !negatable {
+		return fmt.Errorf("cannot be negative")
+	}
+
+	seen := map[[2]int]bool{}
+
+	for i, edge := range e.edges {
+		for _, idx := range edge.Cells {
+			if idx < 0 || idx >= shape.NumCells() {
+				return fmt.Errorf("edge %d has cell %d outside the grid", i+1, idx)
+			}
+		}
+
+		a, b := edge.Cells[0], edge.Cells[1]
+		aRow, aCol := shape.RowCol(a)
+		bRow, bCol := shape.RowCol(b)
+
+		if abs(aRow-bRow)+abs(aCol-bCol) != 1 {
+			return fmt.Errorf("edge %d joins %s and %s, which do not share a side", i+1, shape.CellName(a), shape.CellName(b))
+		}
+
+		if !slices.Contains(markers, edge.Marker) {
+			return fmt.Errorf("edge %d has an unknown marker", i+1)
+		}
+
+		if seen[[2]int{min(a, b), max(a, b)}] {
+			return fmt.Errorf("the side between %s and %s is marked twice", shape.CellName(a), shape.CellName(b))
+		}
+
+		seen[[2]int{min(a, b), max(a, b)}] = true
+	}
+
+	return nil
+}
+
+// neighbors returns the cells across the marked sides of a cell, or every
+// cell sharing a side with it under the negative constraint
+func (e edgeSet) neighbors(shape Shape, idx int) []int {
+	if e.negative {
+		return offsetNeighbors(shape, idx, orthogonalMoves)
+	}
+
+	neighbors := []int{}
+
+	for _, edge := range e.edges {
+		switch idx {
+		case edge.Cells[0]:
+			neighbors = append(neighbors, edge.Cells[1])
+		case edge.Cells[1]:
+			neighbors = append(neighbors, edge.Cells[0])
+		}
+	}
+
+	return neighbors
+}
+
+// excluded returns the digits a cell may not hold beside a neighbor holding
+// digit: those the marker between them does not allow, or under the
+// negative constraint those any of the markers would allow on an unmarked side
+func (e edgeSet) excluded(shape Shape, idx int, neighbor int, digit byte, markers []Marker) Candidates {
+	i, marked := e.index[[2]int{idx, neighbor}]
+	excluded := Candidates(0)
+
+	for d := byte(1); int(d) <= shape.Size(); d++ {
+		switch {
+		case marked:
+			edge := e.edges[i]
+			a, b := d, digit
+
+			if edge.Cells[0] != idx {
+				a, b = digit, d
+			}
+
+			if !edge.Marker.Allows(a, b) {
+				excluded = excluded.Add(d)
+			}
+		case e.negative:
+			for _, marker := range markers {
+				if marker.Allows(d, digit) {
+					excluded = excluded.Add(d)
+				}
+			}
+		}
+	}
+
+	return excluded
+}
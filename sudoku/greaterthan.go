@@ -0,0 +1,36 @@
+package sudoku
+
+// GreaterThan puts signs between cells pointing from the larger digit to
+// the smaller. Since a sign reads differently either way round, it has no
+// negative form.
+type GreaterThan struct {
+	edgeSet
+}
+
+func (GreaterThan) Name() string {
+	return "Greater Than"
+}
+
+func (GreaterThan) Markers() []Marker {
+	return []Marker{GreaterMark}
+}
+
+func (GreaterThan) Negatable() bool {
+	return false
+}
+
+func (GreaterThan) WithEdges(edges []Edge, negative bool) EdgeConstraint {
+	return GreaterThan{newEdgeSet(edges, negative)}
+}
+
+func (g GreaterThan) Validate(shape Shape) error {
+	return g.validate(shape, g.Markers(), g.Negatable())
+}
+
+func (g GreaterThan) Neighbors(shape Shape, idx int) []int {
+	return g.neighbors(shape, idx)
+}
+
+func (g GreaterThan) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return g.excluded(shape, idx, neighbor, digit, g.Markers())
+}
@@ -0,0 +1,61 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Grid holds one digit per cell in row-major order, with 0 for an empty cell
+type Grid []byte
+
+func NewGrid(shape Shape) Grid {
+	return make(Grid, shape.NumCells())
+}
+
+func ParseGrid(shape Shape, text string) (Grid, error) {
+	grid := NewGrid(shape)
+	text = strings.TrimSpace(text)
+
+	if len(text) != len(grid) {
+		return grid, fmt.Errorf("expected %d cells, got %d", len(grid), len(text))
+	}
+
+	for idx := range grid {
+		value, ok := ParseSymbol(text[idx])
+
+		if !ok || int(value) > shape.Size() {
+			return grid, fmt.Errorf("invalid character %q at cell %d", text[idx], idx)
+		}
+
+		grid[idx] = value
+	}
+
+	return grid, nil
+}
+
+func (g Grid) String() string {
+	var sb strings.Builder
+
+	for _, value := range g {
+		sb.WriteByte(Symbol(value))
+	}
+
+	return sb.String()
+}
+
+// Clone copies the grid so it can be changed without touching the original
+func (g Grid) Clone() Grid {
+	return append(Grid(nil), g...)
+}
+
+func (g Grid) Clues() int {
+	clues := 0
+
+	for _, value := range g {
+		if value != 0 {
+			clues++
+		}
+	}
+
+	return clues
+}
@@ -0,0 +1,140 @@
+package sudoku
+
+import "fmt"
+
+type Change struct {
+	Cell   int       `json:"cell"`
+	Before CellState `json:"before"`
+	After  CellState `json:"after"`
+}
+
+// Command is one undoable edit, which may touch several cells
+type Command struct {
+	Name    string   `json:"name"`
+	Changes []Change `json:"changes"`
+}
+
+// History keeps every recorded command so edits can be undone and redone
+type History struct {
+	undo []Command
+	redo []Command
+}
+
+func NewHistory() *History {
+	return &History{}
+}
+
+// RestoreHistory rebuilds a history from commands returned by Commands
+func RestoreHistory(undo []Command, redo []Command) *History {
+	return &History{undo: undo, redo: redo}
+}
+
+// CheckCommands reports the first change in commands that a board of a
+// shape with givens could not have recorded, so a history read from a save
+// cannot reach outside the grid or overwrite a given
+func CheckCommands(shape Shape, givens Grid, commands []Command) error {
+	for i, command := range commands {
+		for _, change := range command.Changes {
+			for _, state := range []CellState{change.Before, change.After} {
+				err := CheckState(shape, givens, change.Cell, state)
+
+				if err != nil {
+					return fmt.Errorf("command %d (%s): %w", i, command.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Commands returns the undo and redo stacks, oldest command first
+func (h *History) Commands() ([]Command, []Command) {
+	return h.undo, h.redo
+}
+
+// Record runs edit against the board and stores every cell it changed as a
+// single command. If edit fails, the board is restored and nothing is recorded.
+func (h *History) Record(b *Board, name string, edit func() error) error {
+	before := make([]CellState, b.Shape().NumCells())
+
+	for idx := range before {
+		before[idx] = b.State(idx)
+	}
+
+	err := edit()
+
+	if err != nil {
+		for idx := range before {
+			if b.State(idx) != before[idx] {
+				b.Restore(idx, before[idx])
+			}
+		}
+
+		return err
+	}
+
+	command := Command{Name: name}
+
+	for idx := range before {
+		after := b.State(idx)
+
+		if after != before[idx] {
+			command.Changes = append(command.Changes, Change{Cell: idx, Before: before[idx], After: after})
+		}
+	}
+
+	if len(command.Changes) > 0 {
+		h.undo = append(h.undo, command)
+		h.redo = nil
+	}
+
+	return nil
+}
+
+func (h *History) Undo(b *Board) (Command, bool) {
+	if len(h.undo) == 0 {
+		return Command{}, false
+	}
+
+	command := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+
+	for i := len(command.Changes) - 1; i >= 0; i-- {
+		b.Restore(command.Changes[i].Cell, command.Changes[i].Before)
+	}
+
+	h.redo = append(h.redo, command)
+
+	return command, true
+}
+
+func (h *History) Redo(b *Board) (Command, bool) {
+	if len(h.redo) == 0 {
+		return Command{}, false
+	}
+
+	command := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+
+	for _, change := range command.Changes {
+		b.Restore(change.Cell, change.After)
+	}
+
+	h.undo = append(h.undo, command)
+
+	return command, true
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+func (h *History) Clear() {
+	h.undo = nil
+	h.redo = nil
+}
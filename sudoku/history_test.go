@@ -0,0 +1,182 @@
+package sudoku
+
+import (
+	"errors"
+	"testing"
+)
+
+// record runs an edit through the history, failing the test if it errors
+func record(t *testing.T, h *History, b *Board, name string, edit func() error) {
+	t.Helper()
+
+	if err := h.Record(b, name, edit); err != nil {
+		t.Fatalf("recording %s: %s", name, err)
+	}
+}
+
+// TestRecordGroup checks a placement and the peer notes it clears undo and
+// redo together as one command
+func TestRecordGroup(t *testing.T) {
+	b := board(t, classicPuzzle)
+	h := NewHistory()
+
+	record(t, h, b, "note", func() error {
+		return b.SetNotes(5, CandidatesOf(4, 6))
+	})
+
+	record(t, h, b, "note", func() error {
+		return b.SetNotes(6, CandidatesOf(4))
+	})
+
+	record(t, h, b, "place", func() error {
+		err := b.Set(2, 4)
+
+		if err == nil {
+			b.RemovePeerNotes(2, 4)
+		}
+
+		return err
+	})
+
+	undo, _ := h.Commands()
+
+	if len(undo) != 3 {
+		t.Fatalf("recorded %d commands, want 3", len(undo))
+	}
+
+	if changes := len(undo[2].Changes); changes != 3 {
+		t.Errorf("placement recorded %d changed cells, want 3", changes)
+	}
+
+	if b.Notes(5) != CandidatesOf(6) || !b.Notes(6).Empty() {
+		t.Fatalf("placing a 4 left peer notes %b and %b", b.Notes(5), b.Notes(6))
+	}
+
+	command, ok := h.Undo(b)
+
+	if !ok || command.Name != "place" {
+		t.Fatalf("undid %q, want the placement", command.Name)
+	}
+
+	if b.Value(2) != 0 || b.Notes(5) != CandidatesOf(4, 6) || b.Notes(6) != CandidatesOf(4) {
+		t.Errorf("one undo left cell 2 at %d with peer notes %b and %b", b.Value(2), b.Notes(5), b.Notes(6))
+	}
+
+	if !h.CanUndo() || !h.CanRedo() {
+		t.Errorf("after one undo can undo %t, can redo %t", h.CanUndo(), h.CanRedo())
+	}
+
+	if _, ok := h.Redo(b); !ok {
+		t.Fatalf("nothing to redo")
+	}
+
+	if b.Value(2) != 4 || b.Notes(5) != CandidatesOf(6) || !b.Notes(6).Empty() {
+		t.Errorf("redo left cell 2 at %d with peer notes %b and %b", b.Value(2), b.Notes(5), b.Notes(6))
+	}
+}
+
+// TestRecordClearsRedo checks a new edit drops the commands left to redo
+func TestRecordClearsRedo(t *testing.T) {
+	b := board(t, classicPuzzle)
+	h := NewHistory()
+
+	record(t, h, b, "place", func() error {
+		return b.Set(2, 4)
+	})
+
+	record(t, h, b, "place", func() error {
+		return b.Set(3, 6)
+	})
+
+	h.Undo(b)
+
+	if !h.CanRedo() {
+		t.Fatalf("nothing to redo after an undo")
+	}
+
+	record(t, h, b, "place", func() error {
+		return b.Set(5, 8)
+	})
+
+	if h.CanRedo() {
+		t.Errorf("redo survived a new edit")
+	}
+
+	if _, ok := h.Redo(b); ok || b.Value(3) != 0 {
+		t.Errorf("redo after a new edit put %d back in cell 3", b.Value(3))
+	}
+
+	_, redo := h.Commands()
+
+	if len(redo) != 0 {
+		t.Errorf("%d commands left to redo", len(redo))
+	}
+}
+
+// TestRecordFailed checks a failing edit is rolled back and not recorded
+func TestRecordFailed(t *testing.T) {
+	b := board(t, classicPuzzle)
+	h := NewHistory()
+
+	err := h.Record(b, "place", func() error {
+		b.Set(2, 4)
+
+		return errors.New("failed")
+	})
+
+	if err == nil {
+		t.Fatalf("failed edit recorded without error")
+	}
+
+	if b.Value(2) != 0 {
+		t.Errorf("failed edit left %d in cell 2", b.Value(2))
+	}
+
+	if h.CanUndo() {
+		t.Errorf("failed edit was recorded")
+	}
+
+	// An edit changing nothing is not recorded either
+	record(t, h, b, "erase", func() error {
+		return b.Erase(2)
+	})
+
+	if h.CanUndo() {
+		t.Errorf("edit without changes was recorded")
+	}
+}
+
+func TestCheckCommands(t *testing.T) {
+	givens := parse(t, Classic, classicPuzzle)
+
+	// change builds a command changing one cell from an empty state
+	change := func(idx int, after CellState) []Command {
+		return []Command{{Name: "edit", Changes: []Change{{Cell: idx, After: after}}}}
+	}
+
+	tests := []struct {
+		name     string
+		commands []Command
+		ok       bool
+	}{
+		{"no commands", nil, true},
+		{"placement", change(2, CellState{Value: 4}), true},
+		{"notes", change(2, CellState{Notes: CandidatesOf(1, 2)}), true},
+		{"given overwritten", change(0, CellState{Value: 4}), false},
+		{"digit out of range", change(2, CellState{Value: 10}), false},
+		{"notes out of range", change(2, CellState{Notes: Classic.AllCandidates() << 1}), false},
+		{"index out of range", change(81, CellState{Value: 4}), false},
+	}
+
+	for _, test := range tests {
+		err := CheckCommands(Classic, givens, test.commands)
+
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: checked without error", test.name)
+		}
+	}
+}
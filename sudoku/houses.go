@@ -0,0 +1,275 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// geometry holds the houses and peers of a shape, built the first time the
+// shape is used
+type geometry struct {
+	rows   [][]int
+	cols   [][]int
+	boxes  [][]int
+	houses [][]int
+	peers  [][]int
+
+	// housesOf lists the houses holding each cell
+	housesOf [][]int
+
+	// grids lists the cells of each grid row by row
+	grids [][]int
+
+	// A multi-grid canvas is width by height cells, with positions holding
+	// the row and column of each cell and index the cell at each position,
+	// or -1 where no grid covers it. boxOf holds the box of each cell.
+	width     int
+	height    int
+	positions [][2]int
+	index     []int
+	boxOf     []int
+}
+
+var geometries [MaxSize + 1][MaxSize + 1]struct {
+	once sync.Once
+	geo  *geometry
+}
+
+// customGeometries holds the geometry of each jigsaw or multi-grid shape
+// seen so far
+var customGeometries sync.Map
+
+func (s Shape) geometry() *geometry {
+	if s.Jigsaw() || s.Multi() {
+		if geo, ok := customGeometries.Load(s); ok {
+			return geo.(*geometry)
+		}
+
+		build := s.buildGeometry
+
+		if s.Multi() {
+			build = s.buildLayoutGeometry
+		}
+
+		geo, _ := customGeometries.LoadOrStore(s, build())
+
+		return geo.(*geometry)
+	}
+
+	entry := &geometries[s.BoxWidth][s.BoxHeight]
+	entry.once.Do(func() { entry.geo = s.buildGeometry() })
+
+	return entry.geo
+}
+
+func (s Shape) buildGeometry() *geometry {
+	size := s.Size()
+	geo := &geometry{width: size, height: size}
+
+	for range size {
+		geo.rows = append(geo.rows, make([]int, size))
+		geo.cols = append(geo.cols, make([]int, size))
+		geo.boxes = append(geo.boxes, make([]int, 0, size))
+	}
+
+	// Visiting cells in order lists each box left to right, top to bottom
+	for idx := range s.NumCells() {
+		row, col := s.RowCol(idx)
+		box := s.BoxOf(idx)
+
+		geo.rows[row][col] = idx
+		geo.cols[col][row] = idx
+		geo.boxes[box] = append(geo.boxes[box], idx)
+		geo.boxOf = append(geo.boxOf, box)
+	}
+
+	geo.houses = append(append(append(geo.houses, geo.rows...), geo.cols...), geo.boxes...)
+	geo.grids = [][]int{slices.Concat(geo.rows...)}
+	geo.indexHouses(s.NumCells())
+
+	return geo
+}
+
+// indexHouses lists the houses of each cell and the peers sharing any of
+// them, excluding the cell itself
+func (geo *geometry) indexHouses(cells int) {
+	geo.housesOf = make([][]int, cells)
+	geo.peers = make([][]int, cells)
+
+	for house, members := range geo.houses {
+		for _, idx := range members {
+			geo.housesOf[idx] = append(geo.housesOf[idx], house)
+		}
+	}
+
+	for idx := range cells {
+		seen := map[int]bool{idx: true}
+		peers := []int{}
+
+		for _, house := range geo.housesOf[idx] {
+			for _, peer := range geo.houses[house] {
+				if !seen[peer] {
+					seen[peer] = true
+					peers = append(peers, peer)
+				}
+			}
+		}
+
+		geo.peers[idx] = peers
+	}
+}
+
+// Index returns the cell at a row and column of the canvas, or -1 where no
+// grid of a layout covers it
+func (s Shape) Index(row int, col int) int {
+	if s.Multi() {
+		geo := s.geometry()
+
+		if row < 0 || row >= geo.height || col < 0 || col >= geo.width {
+			return -1
+		}
+
+		return geo.index[row*geo.width+col]
+	}
+
+	return row*s.Size() + col
+}
+
+func (s Shape) RowCol(idx int) (int, int) {
+	if s.Multi() {
+		pos := s.geometry().positions[idx]
+		return pos[0], pos[1]
+	}
+
+	return idx / s.Size(), idx % s.Size()
+}
+
+func (s Shape) RowOf(idx int) int {
+	row, _ := s.RowCol(idx)
+	return row
+}
+
+func (s Shape) ColOf(idx int) int {
+	_, col := s.RowCol(idx)
+	return col
+}
+
+// BoxOf numbers the boxes left to right, top to bottom, or returns the
+// region of a jigsaw grid
+func (s Shape) BoxOf(idx int) int {
+	if s.Multi() {
+		return s.geometry().boxOf[idx]
+	}
+
+	if s.Jigsaw() {
+		region, _ := ParseSymbol(s.Regions[idx])
+		return int(region) - 1
+	}
+
+	row, col := s.RowCol(idx)
+
+	return (row/s.BoxHeight)*s.BoxHeight + col/s.BoxWidth
+}
+
+func (s Shape) Row(row int) []int {
+	return s.geometry().rows[row]
+}
+
+func (s Shape) Col(col int) []int {
+	return s.geometry().cols[col]
+}
+
+func (s Shape) Box(box int) []int {
+	return s.geometry().boxes[box]
+}
+
+// NumBoxes is the number of boxes, which a layout has fewer of than its
+// grids do since some are shared
+func (s Shape) NumBoxes() int {
+	return len(s.geometry().boxes)
+}
+
+// BoxHouse returns the house of a box numbered as in BoxOf
+func (s Shape) BoxHouse(box int) int {
+	return s.NumHouses() - s.NumBoxes() + box
+}
+
+// LineHouses returns the houses of every row and column
+func (s Shape) LineHouses() []int {
+	lines := []int{}
+
+	for house := range s.NumHouses() - s.NumBoxes() {
+		lines = append(lines, house)
+	}
+
+	return lines
+}
+
+// HousesOf returns the houses holding a cell, in the order of Houses. A
+// cell shared by several grids of a layout is in a row and column of each.
+func (s Shape) HousesOf(idx int) []int {
+	return s.geometry().housesOf[idx]
+}
+
+// Houses returns every row, then every column, then every box. The rows and
+// columns of a layout are listed grid by grid.
+func (s Shape) Houses() [][]int {
+	return s.geometry().houses
+}
+
+// House returns the cells of a house numbered in the same order as Houses
+func (s Shape) House(house int) []int {
+	return s.geometry().houses[house]
+}
+
+func (s Shape) HouseName(house int) string {
+	size := s.Size()
+
+	if s.Multi() {
+		lines := len(s.geometry().rows)
+
+		switch {
+		case house < lines:
+			return fmt.Sprintf("grid %d row %d", house/size+1, house%size+1)
+		case house < 2*lines:
+			return fmt.Sprintf("grid %d column %d", (house-lines)/size+1, (house-lines)%size+1)
+		default:
+			return fmt.Sprintf("box %d", house-2*lines+1)
+		}
+	}
+
+	switch {
+	case house < size:
+		return fmt.Sprintf("row %d", house+1)
+	case house < 2*size:
+		return fmt.Sprintf("column %d", house-size+1)
+	case s.Jigsaw():
+		return fmt.Sprintf("region %d", house-2*size+1)
+	default:
+		return fmt.Sprintf("box %d", house-2*size+1)
+	}
+}
+
+func (s Shape) CellName(idx int) string {
+	row, col := s.RowCol(idx)
+
+	return fmt.Sprintf("r%dc%d", row+1, col+1)
+}
+
+// Peers returns a slice shared by every caller, which must not be modified
+func (s Shape) Peers(idx int) []int {
+	return s.geometry().peers[idx]
+}
+
+func (s Shape) IsPeer(a int, b int) bool {
+	if a == b {
+		return false
+	}
+
+	if s.Multi() {
+		return slices.Contains(s.Peers(a), b)
+	}
+
+	return s.RowOf(a) == s.RowOf(b) || s.ColOf(a) == s.ColOf(b) || s.BoxOf(a) == s.BoxOf(b)
+}
@@ -0,0 +1,36 @@
+package sudoku
+
+// Kropki joins cells with white dots between consecutive digits and black
+// dots between digits one of which is double the other. A pair of 1 and 2
+// may take either.
+type Kropki struct {
+	edgeSet
+}
+
+func (Kropki) Name() string {
+	return "Kropki"
+}
+
+func (Kropki) Markers() []Marker {
+	return []Marker{WhiteDot, BlackDot}
+}
+
+func (Kropki) Negatable() bool {
+	return true
+}
+
+func (Kropki) WithEdges(edges []Edge, negative bool) EdgeConstraint {
+	return Kropki{newEdgeSet(edges, negative)}
+}
+
+func (k Kropki) Validate(shape Shape) error {
+	return k.validate(shape, k.Markers(), k.Negatable())
+}
+
+func (k Kropki) Neighbors(shape Shape, idx int) []int {
+	return k.neighbors(shape, idx)
+}
+
+func (k Kropki) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return k.excluded(shape, idx, neighbor, digit, k.Markers())
+}
@@ -0,0 +1,217 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout places several grids on one canvas, overlapping where they share
+// boxes. The grids share their shape and each holds every digit once per
+// row, column and box of its own.
+type Layout struct {
+	Name string
+
+	// Origins holds the top left box of each grid as a box row and column
+	Origins [][2]int
+}
+
+var layouts = []Layout{
+	{Name: "Twin", Origins: [][2]int{{0, 0}, {2, 2}}},
+	{Name: "Butterfly", Origins: [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	{Name: "Samurai", Origins: [][2]int{{0, 0}, {0, 4}, {2, 2}, {4, 0}, {4, 4}}},
+}
+
+// Layouts lists the multi-grid layouts, fewest grids first
+func Layouts() []Layout {
+	return layouts
+}
+
+func LayoutByName(name string) (Layout, error) {
+	for _, layout := range layouts {
+		if strings.EqualFold(layout.Name, name) {
+			return layout, nil
+		}
+	}
+
+	return Layout{}, fmt.Errorf("unknown layout: %s", name)
+}
+
+// MultiShapes lists a classic shape for each layout, in the same order
+func MultiShapes() []Shape {
+	shapes := []Shape{}
+
+	for _, layout := range layouts {
+		shapes = append(shapes, Shape{BoxWidth: Classic.BoxWidth, BoxHeight: Classic.BoxHeight, Layout: layout.Name})
+	}
+
+	return shapes
+}
+
+// MultiShapeForCells picks the multi-grid shape whose grids written one after
+// another fill the given number of cells
+func MultiShapeForCells(cells int) (Shape, error) {
+	for _, shape := range MultiShapes() {
+		if shape.NumGrids()*shape.Size()*shape.Size() == cells {
+			return shape, nil
+		}
+	}
+
+	return Shape{}, fmt.Errorf("no layout of grids has %d cells", cells)
+}
+
+// Multi reports whether the shape places several grids on one canvas
+func (s Shape) Multi() bool {
+	return s.Layout != ""
+}
+
+// Single returns one grid of the shape on its own
+func (s Shape) Single() Shape {
+	return Shape{BoxWidth: s.BoxWidth, BoxHeight: s.BoxHeight}
+}
+
+func (s Shape) validateLayout() error {
+	layout, err := LayoutByName(s.Layout)
+
+	if err != nil {
+		return err
+	}
+
+	// Every layout is drawn for grids three boxes on a side
+	if s.BoxWidth != 3 || s.BoxHeight != 3 {
+		return fmt.Errorf("the %s layout needs %s grids", layout.Name, Classic)
+	}
+
+	if s.Jigsaw() {
+		return fmt.Errorf("the %s layout cannot have jigsaw regions", layout.Name)
+	}
+
+	return nil
+}
+
+// buildLayoutGeometry lays the grids of a layout out on a canvas, numbering
+// the cells they cover row by row. Rows and columns are listed grid by grid,
+// while a box shared by several grids is listed once.
+func (s Shape) buildLayoutGeometry() *geometry {
+	layout, _ := LayoutByName(s.Layout)
+	size := s.Size()
+	geo := &geometry{}
+
+	for _, origin := range layout.Origins {
+		geo.height = max(geo.height, origin[0]*s.BoxHeight+size)
+		geo.width = max(geo.width, origin[1]*s.BoxWidth+size)
+	}
+
+	covered := make([]bool, geo.width*geo.height)
+
+	for _, origin := range layout.Origins {
+		for r := range size {
+			for c := range size {
+				covered[(origin[0]*s.BoxHeight+r)*geo.width+origin[1]*s.BoxWidth+c] = true
+			}
+		}
+	}
+
+	geo.index = make([]int, len(covered))
+	boxNumbers := map[int]int{}
+
+	for pos, ok := range covered {
+		geo.index[pos] = -1
+
+		if !ok {
+			continue
+		}
+
+		idx := len(geo.positions)
+		row, col := pos/geo.width, pos%geo.width
+		key := (row/s.BoxHeight)*geo.width + col/s.BoxWidth
+
+		if _, seen := boxNumbers[key]; !seen {
+			boxNumbers[key] = len(geo.boxes)
+			geo.boxes = append(geo.boxes, []int{})
+		}
+
+		box := boxNumbers[key]
+
+		geo.index[pos] = idx
+		geo.positions = append(geo.positions, [2]int{row, col})
+		geo.boxOf = append(geo.boxOf, box)
+		geo.boxes[box] = append(geo.boxes[box], idx)
+	}
+
+	for _, origin := range layout.Origins {
+		grid := []int{}
+
+		for r := range size {
+			row := []int{}
+			col := []int{}
+
+			for c := range size {
+				row = append(row, geo.index[(origin[0]*s.BoxHeight+r)*geo.width+origin[1]*s.BoxWidth+c])
+				col = append(col, geo.index[(origin[0]*s.BoxHeight+c)*geo.width+origin[1]*s.BoxWidth+r])
+			}
+
+			geo.rows = append(geo.rows, row)
+			geo.cols = append(geo.cols, col)
+			grid = append(grid, row...)
+		}
+
+		geo.grids = append(geo.grids, grid)
+	}
+
+	geo.houses = append(append(append(geo.houses, geo.rows...), geo.cols...), geo.boxes...)
+	geo.indexHouses(len(geo.positions))
+
+	return geo
+}
+
+// NumGrids is the number of grids the shape places, which is one unless it
+// has a layout
+func (s Shape) NumGrids() int {
+	return len(s.geometry().grids)
+}
+
+// GridCells returns the cells of one grid of a layout row by row, as they
+// would be numbered in a grid of their own
+func (s Shape) GridCells(grid int) []int {
+	return s.geometry().grids[grid]
+}
+
+// GridRows returns the houses of the rows of one grid, top to bottom
+func (s Shape) GridRows(grid int) []int {
+	rows := []int{}
+
+	for r := range s.Size() {
+		rows = append(rows, grid*s.Size()+r)
+	}
+
+	return rows
+}
+
+// GridCols returns the houses of the columns of one grid, left to right
+func (s Shape) GridCols(grid int) []int {
+	cols := []int{}
+
+	for c := range s.Size() {
+		cols = append(cols, len(s.geometry().rows)+grid*s.Size()+c)
+	}
+
+	return cols
+}
+
+// Width and Height give the size of the canvas in cells, which for a single
+// grid is the grid itself
+func (s Shape) Width() int {
+	if s.Multi() {
+		return s.geometry().width
+	}
+
+	return s.Size()
+}
+
+func (s Shape) Height() int {
+	if s.Multi() {
+		return s.geometry().height
+	}
+
+	return s.Size()
+}
@@ -0,0 +1,193 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LineConstraint is a constraint drawn as lines through the grid, each
+// putting a rule on the digits along it. Unlike the other constraints it
+// holds the cells of its lines, so every puzzle draws its own.
+type LineConstraint interface {
+	Constraint
+
+	// Lines returns the cells of each line in order
+	Lines() [][]int
+
+	// WithLines returns the same kind of constraint over other lines
+	WithLines(lines [][]int) LineConstraint
+
+	// Allows reports whether the digits along one line, with 0 for an empty
+	// cell, can still be completed to follow the rule
+	Allows(shape Shape, digits []byte) bool
+}
+
+// AllLineConstraints lists every kind of line constraint, each without lines,
+// in the order the interface offers them
+func AllLineConstraints() []LineConstraint {
+	return []LineConstraint{Thermo{}, Arrow{}, Palindrome{}, Renban{}}
+}
+
+// LineStyle picks how the lines of an overlay are drawn
+type LineStyle int
+
+const (
+	PlainLine LineStyle = iota
+	ThermoLine
+	ArrowLine
+	PalindromeLine
+	RenbanLine
+)
+
+// lineSet holds the lines of a line constraint, no two of which share a cell
+type lineSet struct {
+	lines [][]int
+}
+
+func newLineSet(lines [][]int) lineSet {
+	set := lineSet{}
+
+	for _, line := range lines {
+		set.lines = append(set.lines, slices.Clone(line))
+	}
+
+	return set
+}
+
+func (l lineSet) Lines() [][]int {
+	return l.lines
+}
+
+func (l lineSet) Houses(shape Shape) [][]int {
+	return nil
+}
+
+// validate checks that each line has between minimum and maximum cells
+// inside the grid, each a king's move on from the one before, and that no
+// cell is on two lines
+func (l lineSet) validate(shape Shape, minimum int, maximum int) error {
+	seen := map[int]bool{}
+
+	for i, line := range l.lines {
+		if len(line) < minimum || len(line) > maximum {
+			return fmt.Errorf("line %d has %d cells, expected %d to %d", i+1, len(line), minimum, maximum)
+		}
+
+		for j, idx := range line {
+			if idx < 0 || idx >= shape.NumCells() {
+				return fmt.Errorf("line %d has cell %d outside the grid", i+1, idx)
+			}
+
+			if seen[idx] {
+				return fmt.Errorf("cell %s is on more than one line", shape.CellName(idx))
+			}
+
+			seen[idx] = true
+
+			if j > 0 && !kingsMove(shape, line[j-1], idx) {
+				return fmt.Errorf("line %d jumps from %s to %s", i+1, shape.CellName(line[j-1]), shape.CellName(idx))
+			}
+		}
+	}
+
+	return nil
+}
+
+// find returns the line a cell is on and its position along it, or -1 for
+// both when the cell is on none
+func (l lineSet) find(idx int) (int, int) {
+	for i, line := range l.lines {
+		if pos := slices.Index(line, idx); pos >= 0 {
+			return i, pos
+		}
+	}
+
+	return -1, -1
+}
+
+// others returns the rest of the cells on the line a cell is on
+func (l lineSet) others(idx int) []int {
+	line, _ := l.find(idx)
+
+	if line < 0 {
+		return nil
+	}
+
+	others := []int{}
+
+	for _, cell := range l.lines[line] {
+		if cell != idx {
+			others = append(others, cell)
+		}
+	}
+
+	return others
+}
+
+func kingsMove(shape Shape, from int, to int) bool {
+	fromRow, fromCol := shape.RowCol(from)
+	toRow, toCol := shape.RowCol(to)
+
+	return from != to && abs(fromRow-toRow) <= 1 && abs(fromCol-toCol) <= 1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// LineDigits returns the values placed along a line, with 0 for empty cells
+func LineDigits(values []byte, line []int) []byte {
+	digits := make([]byte, len(line))
+
+	for i, idx := range line {
+		digits[i] = values[idx]
+	}
+
+	return digits
+}
+
+// LinesAllow reports whether every line through a cell can still follow its
+// rule given the values placed in the grid
+func (c Constraints) LinesAllow(shape Shape, values []byte, idx int) bool {
+	for _, constraint := range c {
+		lines, ok := constraint.(LineConstraint)
+
+		if !ok {
+			continue
+		}
+
+		for _, line := range lines.Lines() {
+			if slices.Contains(line, idx) && !lines.Allows(shape, LineDigits(values, line)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// BrokenLines returns every line whose placed values can no longer follow
+// its rule
+func (c Constraints) BrokenLines(shape Shape, values []byte) [][]int {
+	broken := [][]int{}
+
+	for _, constraint := range c {
+		lines, ok := constraint.(LineConstraint)
+
+		if !ok {
+			continue
+		}
+
+		for _, line := range lines.Lines() {
+			if !lines.Allows(shape, LineDigits(values, line)) {
+				broken = append(broken, line)
+			}
+		}
+	}
+
+	return broken
+}
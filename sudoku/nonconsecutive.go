@@ -0,0 +1,38 @@
+package sudoku
+
+import "fmt"
+
+var orthogonalMoves = [][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
+
+// NonConsecutive forbids digits one apart in cells sharing a side
+type NonConsecutive struct{}
+
+func (NonConsecutive) Name() string {
+	return "Non-Consecutive"
+}
+
+// Validate rejects the 4x4 grid, whose rows can only be 2413 or 3142 and so
+// cannot also make distinct columns
+func (NonConsecutive) Validate(shape Shape) error {
+	if shape.Size() <= 4 {
+		return fmt.Errorf("no %s grid can follow the rule", shape)
+	}
+
+	return nil
+}
+
+func (NonConsecutive) Houses(shape Shape) [][]int {
+	return nil
+}
+
+func (NonConsecutive) Neighbors(shape Shape, idx int) []int {
+	return offsetNeighbors(shape, idx, orthogonalMoves)
+}
+
+func (NonConsecutive) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return CandidatesOf(digit-1, digit+1) & shape.AllCandidates()
+}
+
+func (NonConsecutive) Overlay(shape Shape) Overlay {
+	return Overlay{}
+}
@@ -0,0 +1,54 @@
+package sudoku
+
+// Palindrome adds lines that read the same from either end
+type Palindrome struct {
+	lineSet
+}
+
+func (Palindrome) Name() string {
+	return "Palindrome"
+}
+
+func (Palindrome) WithLines(lines [][]int) LineConstraint {
+	return Palindrome{newLineSet(lines)}
+}
+
+func (p Palindrome) Validate(shape Shape) error {
+	return p.validate(shape, 2, shape.NumCells())
+}
+
+// Neighbors returns the cell the same distance from the other end of the
+// line, which the middle cell of an odd line lacks
+func (p Palindrome) Neighbors(shape Shape, idx int) []int {
+	line, pos := p.find(idx)
+
+	if line < 0 {
+		return nil
+	}
+
+	mirror := p.lines[line][len(p.lines[line])-1-pos]
+
+	if mirror == idx {
+		return nil
+	}
+
+	return []int{mirror}
+}
+
+func (Palindrome) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return shape.AllCandidates() &^ CandidatesOf(digit)
+}
+
+func (Palindrome) Allows(shape Shape, digits []byte) bool {
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		if digits[i] != 0 && digits[j] != 0 && digits[i] != digits[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p Palindrome) Overlay(shape Shape) Overlay {
+	return Overlay{Lines: p.lines, Style: PalindromeLine}
+}
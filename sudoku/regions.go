@@ -0,0 +1,118 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Jigsaw reports whether the grid has irregular regions in place of boxes
+func (s Shape) Jigsaw() bool {
+	return s.Regions != ""
+}
+
+// Regular returns the shape with its regions replaced by regular boxes
+func (s Shape) Regular() Shape {
+	return Shape{BoxWidth: s.BoxWidth, BoxHeight: s.BoxHeight}
+}
+
+// WithRegions returns the jigsaw shape whose boxes are the given regions, one
+// number per cell. The regions are renumbered in the order they first appear
+// so that every layout has a single spelling.
+func (s Shape) WithRegions(regions []int) (Shape, error) {
+	if len(regions) != s.NumCells() {
+		return Shape{}, fmt.Errorf("expected regions for %d cells, got %d", s.NumCells(), len(regions))
+	}
+
+	numbers := map[int]int{}
+
+	var sb strings.Builder
+
+	for _, region := range regions {
+		number, ok := numbers[region]
+
+		if !ok {
+			number = len(numbers)
+			numbers[region] = number
+		}
+
+		if number >= s.Size() {
+			return Shape{}, fmt.Errorf("a %s grid has at most %d regions", s, s.Size())
+		}
+
+		sb.WriteByte(Symbol(byte(number + 1)))
+	}
+
+	jigsaw := Shape{BoxWidth: s.BoxWidth, BoxHeight: s.BoxHeight, Regions: sb.String()}
+
+	return jigsaw, jigsaw.Validate()
+}
+
+// ParseRegions reads a region map written as one character per cell, where
+// cells sharing a character share a region
+func (s Shape) ParseRegions(text string) (Shape, error) {
+	regions := []int{}
+
+	for _, ch := range []byte(text) {
+		regions = append(regions, int(ch))
+	}
+
+	return s.WithRegions(regions)
+}
+
+// validateRegions checks that every region is named by a symbol of the grid
+// and is a connected group of as many cells as the grid has digits
+func (s Shape) validateRegions() error {
+	if len(s.Regions) != s.NumCells() {
+		return fmt.Errorf("expected regions for %d cells, got %d", s.NumCells(), len(s.Regions))
+	}
+
+	counts := make([]int, s.Size())
+
+	for idx := range s.Regions {
+		region, ok := ParseSymbol(s.Regions[idx])
+
+		if !ok || region == 0 || int(region) > s.Size() {
+			return fmt.Errorf("invalid region %q at %s", s.Regions[idx], s.CellName(idx))
+		}
+
+		counts[region-1]++
+	}
+
+	for region, count := range counts {
+		if count != s.Size() {
+			return fmt.Errorf("region %d has %d cells, expected %d", region+1, count, s.Size())
+		}
+	}
+
+	// Flood each region from its first cell and make sure it reaches the rest
+	seen := make([]bool, s.NumCells())
+
+	for start := range s.Regions {
+		if seen[start] {
+			continue
+		}
+
+		reached := 1
+		queue := []int{start}
+		seen[start] = true
+
+		for len(queue) > 0 {
+			idx := queue[0]
+			queue = queue[1:]
+
+			for _, next := range offsetNeighbors(s, idx, orthogonalMoves) {
+				if !seen[next] && s.Regions[next] == s.Regions[start] {
+					seen[next] = true
+					reached++
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		if reached != s.Size() {
+			return fmt.Errorf("region %c is split into pieces", s.Regions[start])
+		}
+	}
+
+	return nil
+}
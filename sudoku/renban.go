@@ -0,0 +1,55 @@
+package sudoku
+
+// Renban adds lines holding a run of consecutive digits in any order
+type Renban struct {
+	lineSet
+}
+
+func (Renban) Name() string {
+	return "Renban"
+}
+
+func (Renban) WithLines(lines [][]int) LineConstraint {
+	return Renban{newLineSet(lines)}
+}
+
+func (r Renban) Validate(shape Shape) error {
+	return r.validate(shape, 2, shape.Size())
+}
+
+func (r Renban) Neighbors(shape Shape, idx int) []int {
+	return r.others(idx)
+}
+
+// Excluded rules out the digit itself and any digit too far from it to share
+// a run as long as the line. Together these make a full line consecutive.
+func (r Renban) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	line, _ := r.find(idx)
+	length := len(r.lines[line])
+
+	return CandidatesOf(digit) | CandidatesBetween(1, int(digit)-length) | CandidatesBetween(int(digit)+length, shape.Size())
+}
+
+func (Renban) Allows(shape Shape, digits []byte) bool {
+	seen := Candidates(0)
+	low, high := shape.Size(), 1
+
+	for _, digit := range digits {
+		if digit == 0 {
+			continue
+		}
+
+		if seen.Has(digit) {
+			return false
+		}
+
+		seen = seen.Add(digit)
+		low, high = min(low, int(digit)), max(high, int(digit))
+	}
+
+	return seen.Empty() || high-low < len(digits)
+}
+
+func (r Renban) Overlay(shape Shape) Overlay {
+	return Overlay{Lines: r.lines, Style: RenbanLine}
+}
@@ -0,0 +1,151 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxSize is the most digits a grid can use, which is as many as fit in Candidates
+const MaxSize = 16
+
+// Shape describes a grid by the width and height of its boxes. A grid has as
+// many rows, columns and boxes as a box has cells.
+type Shape struct {
+	BoxWidth  int `json:"box_width"`
+	BoxHeight int `json:"box_height"`
+
+	// Regions replaces the boxes of a jigsaw grid with irregular ones,
+	// holding the symbol of each cell's region in row-major order. It is
+	// empty for a grid of regular boxes.
+	Regions string `json:"regions,omitempty"`
+
+	// Layout names the Layout placing several grids of the shape on one
+	// canvas, or is empty for a single grid
+	Layout string `json:"layout,omitempty"`
+}
+
+// Classic is the 9x9 grid of 3x3 boxes
+var Classic = Shape{BoxWidth: 3, BoxHeight: 3}
+
+// Shapes lists the grids offered to the player, smallest first
+func Shapes() []Shape {
+	return []Shape{
+		{BoxWidth: 2, BoxHeight: 2},
+		{BoxWidth: 3, BoxHeight: 2},
+		Classic,
+		{BoxWidth: 4, BoxHeight: 3},
+		{BoxWidth: 4, BoxHeight: 4},
+	}
+}
+
+// ShapeForCells picks the offered shape with the given number of cells, for
+// reading grids that do not state their box layout
+func ShapeForCells(cells int) (Shape, error) {
+	for _, shape := range Shapes() {
+		if shape.NumCells() == cells {
+			return shape, nil
+		}
+	}
+
+	return Shape{}, fmt.Errorf("no grid has %d cells", cells)
+}
+
+func ShapeForSize(size int) (Shape, error) {
+	return ShapeForCells(size * size)
+}
+
+// ParseShape reads a shape written by String, such as "6x6" or "Samurai"
+func ParseShape(name string) (Shape, error) {
+	for _, shape := range append(Shapes(), MultiShapes()...) {
+		if strings.EqualFold(name, shape.String()) {
+			return shape, nil
+		}
+	}
+
+	return Shape{}, fmt.Errorf("unknown grid size: %s", name)
+}
+
+func (s Shape) Validate() error {
+	if s.BoxWidth < 1 || s.BoxHeight < 1 || s.Size() < 2 || s.Size() > MaxSize {
+		return fmt.Errorf("invalid box size %dx%d", s.BoxWidth, s.BoxHeight)
+	}
+
+	if s.Multi() {
+		return s.validateLayout()
+	}
+
+	if s.Jigsaw() {
+		return s.validateRegions()
+	}
+
+	return nil
+}
+
+// String names a single grid by its size, such as "9x9", and a multi-grid
+// shape by its layout
+func (s Shape) String() string {
+	if s.Multi() {
+		return s.Layout
+	}
+
+	return fmt.Sprintf("%dx%d", s.Size(), s.Size())
+}
+
+// Size is the number of digits, and of cells in each row, column and box
+func (s Shape) Size() int {
+	return s.BoxWidth * s.BoxHeight
+}
+
+func (s Shape) NumCells() int {
+	if s.Multi() {
+		return len(s.geometry().positions)
+	}
+
+	return s.Size() * s.Size()
+}
+
+func (s Shape) NumHouses() int {
+	if s.Multi() {
+		return len(s.geometry().houses)
+	}
+
+	return 3 * s.Size()
+}
+
+// AllCandidates holds every digit of the grid
+func (s Shape) AllCandidates() Candidates {
+	return Candidates(1<<s.Size() - 1)
+}
+
+// Symbol is the character standing for a digit: 1 to 9 followed by A to G,
+// with '.' for an empty cell
+func Symbol(digit byte) byte {
+	switch {
+	case digit == 0:
+		return '.'
+	case digit <= 9:
+		return '0' + digit
+	default:
+		return 'A' + digit - 10
+	}
+}
+
+// ParseSymbol decodes a character written by Symbol, where '0' is also empty
+func ParseSymbol(ch byte) (byte, bool) {
+	switch {
+	case ch == '.' || ch == '0':
+		return 0, true
+	case ch >= '1' && ch <= '9':
+		return ch - '0', true
+	case ch >= 'A' && ch < 'A'+MaxSize-9:
+		return ch - 'A' + 10, true
+	case ch >= 'a' && ch < 'a'+MaxSize-9:
+		return ch - 'a' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+func DigitName(digit byte) string {
+	return string(Symbol(digit))
+}
@@ -0,0 +1,64 @@
+package sudoku
+
+import "slices"
+
+// Thermo adds thermometers, along which digits rise strictly from the bulb
+// at the first cell
+type Thermo struct {
+	lineSet
+}
+
+func (Thermo) Name() string {
+	return "Thermo"
+}
+
+func (Thermo) WithLines(lines [][]int) LineConstraint {
+	return Thermo{newLineSet(lines)}
+}
+
+func (t Thermo) Validate(shape Shape) error {
+	return t.validate(shape, 2, shape.Size())
+}
+
+func (t Thermo) Neighbors(shape Shape, idx int) []int {
+	return t.others(idx)
+}
+
+// Excluded keeps a cell at least as many digits above each cell before it
+// as the steps between them, and as many below each cell after it
+func (t Thermo) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	line, pos := t.find(idx)
+	steps := pos - slices.Index(t.lines[line], neighbor)
+
+	if steps > 0 {
+		return CandidatesBetween(1, int(digit)+steps-1)
+	}
+
+	return CandidatesBetween(int(digit)+steps+1, shape.Size())
+}
+
+// Allows also leaves room below each digit for the cells before it and
+// above for the cells after it
+func (Thermo) Allows(shape Shape, digits []byte) bool {
+	for i, digit := range digits {
+		if digit == 0 {
+			continue
+		}
+
+		if int(digit) < i+1 || int(digit) > shape.Size()-(len(digits)-1-i) {
+			return false
+		}
+
+		for j := i + 1; j < len(digits); j++ {
+			if digits[j] != 0 && int(digits[j])-int(digit) < j-i {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (t Thermo) Overlay(shape Shape) Overlay {
+	return Overlay{Lines: t.lines, Style: ThermoLine}
+}
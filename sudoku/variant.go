@@ -0,0 +1,32 @@
+package sudoku
+
+import "strings"
+
+// Variant holds the rules a puzzle adds to the classic ones: Killer cages
+// and any number of constraints
+type Variant struct {
+	Cages       []Cage
+	Constraints Constraints
+}
+
+func (v Variant) Validate(shape Shape) error {
+	err := shape.ValidateCages(v.Cages)
+
+	if err != nil {
+		return err
+	}
+
+	return v.Constraints.Validate(shape)
+}
+
+// Name describes the variant, such as "Killer Diagonal", or returns an
+// empty string for a classic puzzle
+func (v Variant) Name() string {
+	names := v.Constraints.Names()
+
+	if len(v.Cages) > 0 {
+		names = append([]string{"Killer"}, names...)
+	}
+
+	return strings.Join(names, " ")
+}
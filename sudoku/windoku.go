@@ -0,0 +1,64 @@
+package sudoku
+
+import "errors"
+
+// Windoku adds windows the size of a box, each a cell in from the boxes
+// around it, that hold every digit once
+type Windoku struct{}
+
+func (Windoku) Name() string {
+	return "Windoku"
+}
+
+// Validate requires square boxes so the windows sit evenly between them
+func (Windoku) Validate(shape Shape) error {
+	if shape.BoxWidth != shape.BoxHeight {
+		return errors.New("windows need square boxes")
+	}
+
+	return nil
+}
+
+// Houses returns the windows left to right, top to bottom, leaving a line
+// of cells between neighboring windows
+func (Windoku) Houses(shape Shape) [][]int {
+	box := shape.BoxWidth
+	count := (shape.Size() - 1) / (box + 1)
+	windows := [][]int{}
+
+	for band := range count {
+		for stack := range count {
+			window := []int{}
+			top := 1 + band*(box+1)
+			left := 1 + stack*(box+1)
+
+			for row := top; row < top+box; row++ {
+				for col := left; col < left+box; col++ {
+					window = append(window, shape.Index(row, col))
+				}
+			}
+
+			windows = append(windows, window)
+		}
+	}
+
+	return windows
+}
+
+func (Windoku) Neighbors(shape Shape, idx int) []int {
+	return nil
+}
+
+func (Windoku) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return 0
+}
+
+func (w Windoku) Overlay(shape Shape) Overlay {
+	overlay := Overlay{}
+
+	for _, window := range w.Houses(shape) {
+		overlay.Shaded = append(overlay.Shaded, window...)
+	}
+
+	return overlay
+}
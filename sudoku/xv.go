@@ -0,0 +1,35 @@
+package sudoku
+
+// XV marks sides between digits adding up to 10 with an X and those adding
+// up to 5 with a V
+type XV struct {
+	edgeSet
+}
+
+func (XV) Name() string {
+	return "XV"
+}
+
+func (XV) Markers() []Marker {
+	return []Marker{XMark, VMark}
+}
+
+func (XV) Negatable() bool {
+	return true
+}
+
+func (XV) WithEdges(edges []Edge, negative bool) EdgeConstraint {
+	return XV{newEdgeSet(edges, negative)}
+}
+
+func (x XV) Validate(shape Shape) error {
+	return x.validate(shape, x.Markers(), x.Negatable())
+}
+
+func (x XV) Neighbors(shape Shape, idx int) []int {
+	return x.neighbors(shape, idx)
+}
+
+func (x XV) Excluded(shape Shape, idx int, neighbor int, digit byte) Candidates {
+	return x.excluded(shape, idx, neighbor, digit, x.Markers())
+}